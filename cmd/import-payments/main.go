@@ -0,0 +1,109 @@
+// Command import-payments imports a CSV settlement file from a collection
+// partner. Each row is processed like a payment notification, so rows that
+// were already received are reported as duplicates and never credited twice.
+// Payments are credited by the server's background workers.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/emmrys-jay/gigmile/config"
+	"github.com/emmrys-jay/gigmile/internal/cache"
+	"github.com/emmrys-jay/gigmile/internal/database"
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/service"
+)
+
+func main() {
+	file := flag.String("file", "", "path to the CSV settlement file")
+	provider := flag.String("provider", service.DefaultPaymentProvider, "payment provider that settled the file, e.g. paystack")
+	columns := flag.String("columns", "", "column mapping overrides, e.g. \"customer_id=Customer Ref,transaction_amount=Amount\"")
+	dateLayout := flag.String("date-layout", "", "Go time layout of the transaction_date column")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	overrides, err := models.ParsePaymentImportMapping(*columns)
+	if err != nil {
+		log.Fatalf("Invalid -columns: %v", err)
+	}
+	overrides.DateLayout = *dateLayout
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	importMapping, err := service.NewPaymentImportMapping(cfg.PaymentImportColumns, cfg.PaymentImportDateLayout)
+	if err != nil {
+		log.Fatalf("Invalid PAYMENT_IMPORT_COLUMNS: %v", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open settlement file: %v", err)
+	}
+	defer f.Close()
+
+	// Initialize database
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Initialize Redis cache
+	redisCache, err := cache.NewRedisCache(cfg.RedisHost, cfg.RedisPort, cfg.RedisPassword, cfg.RedisDB)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisCache.Close()
+
+	paymentService := service.NewPaymentService(
+		repository.NewCustomerRepository(db.Pool),
+		repository.NewAccountRepository(db.Pool),
+		repository.NewPaymentNotificationRepository(db.Pool),
+		repository.NewUnitOfWork(db.Pool),
+		redisCache,
+		models.AllocationStrategy(cfg.AllocationStrategy),
+		importMapping,
+	)
+
+	report, err := paymentService.ImportPayments(f, *provider, overrides)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tREFERENCE\tCUSTOMER\tAMOUNT\tSTATUS\tERROR")
+	for _, row := range report.Results {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", row.Line, row.Reference, row.CustomerID, row.Amount, row.Status, row.Error)
+	}
+	tw.Flush()
+
+	fmt.Printf("\n%d rows:", report.Rows)
+	for _, status := range []models.PaymentImportRowStatus{
+		models.PaymentImportQueued,
+		models.PaymentImportPending,
+		models.PaymentImportDeclined,
+		models.PaymentImportDuplicate,
+		models.PaymentImportUnknownCustomer,
+		models.PaymentImportInvalidAmount,
+		models.PaymentImportInvalid,
+		models.PaymentImportFailed,
+	} {
+		if n := report.Summary[status]; n > 0 {
+			fmt.Printf(" %d %s", n, status)
+		}
+	}
+	fmt.Println()
+}
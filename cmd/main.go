@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/emmrys-jay/gigmile/config"
 	"github.com/emmrys-jay/gigmile/internal/cache"
 	"github.com/emmrys-jay/gigmile/internal/database"
+	"github.com/emmrys-jay/gigmile/internal/middleware"
+	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/emmrys-jay/gigmile/internal/repository"
 	"github.com/emmrys-jay/gigmile/internal/router"
 	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/worker"
 )
 
 func main() {
@@ -38,16 +43,91 @@ func main() {
 	customerRepo := repository.NewCustomerRepository(db.Pool)
 	accountRepo := repository.NewAccountRepository(db.Pool)
 	transactionRepo := repository.NewTransactionRepository(db.Pool)
+	ledgerRepo := repository.NewLedgerRepository(db.Pool)
+	paymentNotificationRepo := repository.NewPaymentNotificationRepository(db.Pool)
+	jobRepo := repository.NewJobRepository(db.Pool)
+	scheduleRepo := repository.NewScheduleRepository(db.Pool)
+	productRepo := repository.NewProductRepository(db.Pool)
+	allocationRepo := repository.NewAllocationRepository(db.Pool)
+	arrearsRepo := repository.NewArrearsRepository(db.Pool)
+	reportRepo := repository.NewReportRepository(db.Pool)
+	penaltyRepo := repository.NewPenaltyRepository(db.Pool)
+	suspenseRepo := repository.NewSuspenseRepository(db.Pool)
+	deploymentRepo := repository.NewDeploymentRepository(db.Pool)
+	assetRepo := repository.NewAssetRepository(db.Pool)
+	guarantorRepo := repository.NewGuarantorRepository(db.Pool)
+	uow := repository.NewUnitOfWork(db.Pool)
+
+	// Settlement file columns for bulk payment imports
+	importMapping, err := service.NewPaymentImportMapping(cfg.PaymentImportColumns, cfg.PaymentImportDateLayout)
+	if err != nil {
+		log.Fatalf("Invalid PAYMENT_IMPORT_COLUMNS: %v", err)
+	}
+
+	// Limits checked before every deployment
+	eligibilityPolicy, err := service.NewEligibilityPolicy(cfg.MaxConcurrentDeployments, cfg.MaxOutstandingExposure, cfg.MinDeploymentDeposit, cfg.MinVerifiedGuarantors)
+	if err != nil {
+		log.Fatalf("Invalid deployment eligibility config: %v", err)
+	}
 
 	// Initialize services
-	customerService := service.NewCustomerService(customerRepo, accountRepo)
-	paymentService := service.NewPaymentService(customerRepo, accountRepo, transactionRepo, redisCache)
-	deploymentService := service.NewDeploymentService(customerRepo, accountRepo, transactionRepo, redisCache)
-	transactionService := service.NewTransactionService(transactionRepo)
-	accountService := service.NewAccountService(accountRepo)
+	customerService := service.NewCustomerService(customerRepo, uow)
+	paymentService := service.NewPaymentService(customerRepo, accountRepo, paymentNotificationRepo, uow, redisCache, models.AllocationStrategy(cfg.AllocationStrategy), importMapping)
+	deploymentService := service.NewDeploymentService(customerRepo, accountRepo, productRepo, scheduleRepo, deploymentRepo, uow, redisCache, eligibilityPolicy)
+	transactionService := service.NewTransactionService(transactionRepo, allocationRepo, uow, redisCache)
+	accountService := service.NewAccountService(accountRepo, ledgerRepo, arrearsRepo, transactionRepo)
+	productService := service.NewProductService(productRepo)
+	arrearsService := service.NewArrearsService(arrearsRepo)
+	reportService := service.NewReportService(reportRepo)
+	penaltyService := service.NewPenaltyService(penaltyRepo, productRepo, uow, redisCache)
+	statementService := service.NewStatementService(customerRepo, accountRepo, transactionRepo)
+	reconciliationService := service.NewReconciliationService(accountRepo, uow, redisCache)
+	suspenseService := service.NewSuspenseService(suspenseRepo, uow)
+	assetService := service.NewAssetService(assetRepo, productRepo, deploymentRepo, uow)
+	guarantorService := service.NewGuarantorService(customerRepo, guarantorRepo, uow)
+
+	// Start background job workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	workerPool := worker.NewPool(jobRepo, worker.Options{
+		Concurrency:  cfg.WorkerConcurrency,
+		PollInterval: cfg.WorkerPollInterval,
+	})
+	workerPool.Register(service.PaymentNotificationQueue, paymentService.HandleNotificationJob)
+	workerPool.Start(ctx)
+
+	// Charge late fees on overdue installments
+	go worker.RunPeriodically(ctx, "penalty evaluation", cfg.PenaltyEvaluationInterval, func() error {
+		evaluation, err := penaltyService.EvaluatePenalties(time.Now(), false)
+		if err == nil && evaluation.Charged > 0 {
+			log.Printf("Charged %d late fees totalling %s", evaluation.Charged, evaluation.Amount)
+		}
+		return err
+	})
+
+	// Default deployments that have fallen too far behind
+	go worker.RunPeriodically(ctx, "deployment status", cfg.DeploymentStatusInterval, func() error {
+		defaulted, err := deploymentService.MarkDefaulted(time.Now(), cfg.DeploymentDefaultDays)
+		if err == nil && len(defaulted) > 0 {
+			log.Printf("Marked %d deployments as defaulted", len(defaulted))
+		}
+		return err
+	})
+
+	// Payment notifications must be signed by a configured provider, in that
+	// provider's own way
+	signatureSchemes := map[string]middleware.SignatureScheme{}
+	for _, provider := range service.PaymentProviders() {
+		signatureSchemes[provider.Name()] = provider
+	}
+	webhookVerifier := middleware.NewWebhookVerifier(cfg.WebhookSecrets, signatureSchemes, cfg.WebhookTolerance)
+
+	// Admin endpoints that move money need an admin API key
+	adminAuth := middleware.NewAdminAuth(cfg.AdminAPIKeys)
 
 	// Initialize router
-	r := router.NewRouter(customerService, paymentService, deploymentService, transactionService, accountService)
+	r := router.NewRouter(customerService, paymentService, deploymentService, transactionService, accountService, productService, arrearsService, reportService, penaltyService, statementService, reconciliationService, suspenseService, assetService, guarantorService, webhookVerifier, adminAuth)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.ServerPort)
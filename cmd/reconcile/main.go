@@ -0,0 +1,88 @@
+// Command reconcile compares every account balance with the balance expected
+// from its transaction history and reports the mismatches. It changes nothing
+// unless run with -apply, which writes an ADJUSTMENT transaction for each
+// mismatched account. It exits with status 1 if mismatches remain.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/emmrys-jay/gigmile/config"
+	"github.com/emmrys-jay/gigmile/internal/cache"
+	"github.com/emmrys-jay/gigmile/internal/database"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "write ADJUSTMENT transactions for mismatched accounts (default is a dry run)")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Initialize Redis cache so adjusted accounts are not served stale
+	redisCache, err := cache.NewRedisCache(cfg.RedisHost, cfg.RedisPort, cfg.RedisPassword, cfg.RedisDB)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisCache.Close()
+
+	accountRepo := repository.NewAccountRepository(db.Pool)
+	uow := repository.NewUnitOfWork(db.Pool)
+	reconciliationService := service.NewReconciliationService(accountRepo, uow, redisCache)
+
+	report, err := reconciliationService.Reconcile(!*apply)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "ACCOUNT\tCUSTOMER\tBALANCE\tEXPECTED\tDIFFERENCE\tADJUSTMENT\t")
+	for _, mismatch := range report.Mismatches {
+		adjustment := "-"
+		if mismatch.AdjustmentID != nil {
+			adjustment = utils.FormatTransactionID(*mismatch.AdjustmentID)
+		} else if !report.DryRun && mismatch.Difference() == 0 {
+			adjustment = "resolved"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			utils.FormatAccountID(mismatch.AccountID),
+			utils.FormatCustomerID(mismatch.CustomerID),
+			mismatch.Balance,
+			mismatch.ExpectedBalance,
+			mismatch.Difference(),
+			adjustment,
+		)
+	}
+	tw.Flush()
+
+	mode := "dry run"
+	if *apply {
+		mode = "applied"
+	}
+	fmt.Printf("\n%d accounts checked, %d mismatched, %d adjusted, %d resolved while running (%s)\n",
+		report.AccountsChecked, len(report.Mismatches), report.Adjusted, report.Resolved, mode)
+
+	if report.Unresolved() > 0 {
+		db.Close()
+		redisCache.Close()
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +22,37 @@ type Config struct {
 	RedisPort     string
 	RedisPassword string
 	RedisDB       int
+
+	WorkerConcurrency  int
+	WorkerPollInterval time.Duration
+
+	AllocationStrategy string
+
+	PenaltyEvaluationInterval time.Duration
+
+	PaymentImportColumns    string
+	PaymentImportDateLayout string
+
+	// WebhookSecrets maps a payment provider name to the secret it signs
+	// notifications with
+	WebhookSecrets   map[string]string
+	WebhookTolerance time.Duration
+
+	// AdminAPIKeys maps each admin actor to the bearer token they authenticate
+	// with on admin endpoints that move money; when empty those endpoints
+	// reject every request
+	AdminAPIKeys map[string]string
+
+	// Deployment eligibility limits; zero or empty is not enforced
+	MaxConcurrentDeployments int
+	MaxOutstandingExposure   string
+	MinDeploymentDeposit     string
+	MinVerifiedGuarantors    int
+
+	// DeploymentDefaultDays is how long an installment may stay unpaid before
+	// its deployment is DEFAULTED
+	DeploymentDefaultDays    int
+	DeploymentStatusInterval time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -33,6 +66,70 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	workerConcurrency := 4
+	if n, err := strconv.Atoi(getEnv("WORKER_CONCURRENCY", "4")); err == nil && n > 0 {
+		workerConcurrency = n
+	}
+
+	workerPollInterval := time.Second
+	if d, err := time.ParseDuration(getEnv("WORKER_POLL_INTERVAL", "1s")); err == nil && d > 0 {
+		workerPollInterval = d
+	}
+
+	// Payments are allocated to the oldest installment first unless LIFO is set
+	allocationStrategy := strings.ToUpper(getEnv("ALLOCATION_STRATEGY", "FIFO"))
+	if allocationStrategy != "FIFO" && allocationStrategy != "LIFO" {
+		return nil, fmt.Errorf("invalid ALLOCATION_STRATEGY %q: must be FIFO or LIFO", allocationStrategy)
+	}
+
+	// The penalty evaluator charges at most once per installment per day and
+	// only charges the day it runs on, so running it more often than daily
+	// keeps short outages from skipping a day. Days missed entirely are waived.
+	penaltyEvaluationInterval := time.Hour
+	if d, err := time.ParseDuration(getEnv("PENALTY_EVALUATION_INTERVAL", "1h")); err == nil && d > 0 {
+		penaltyEvaluationInterval = d
+	}
+
+	webhookSecrets, err := parseSecrets("WEBHOOK_SECRETS", "provider:secret", getEnv("WEBHOOK_SECRETS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Admin actions are recorded under the actor whose key authenticated them
+	adminAPIKeys, err := parseSecrets("ADMIN_API_KEYS", "actor:key", getEnv("ADMIN_API_KEYS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Notifications signed further from the current time than this are rejected
+	// as replays
+	webhookTolerance := 5 * time.Minute
+	if d, err := time.ParseDuration(getEnv("WEBHOOK_TOLERANCE", "5m")); err == nil && d > 0 {
+		webhookTolerance = d
+	}
+
+	// One bike per rider unless configured otherwise
+	maxConcurrentDeployments := 1
+	if n, err := strconv.Atoi(getEnv("MAX_CONCURRENT_DEPLOYMENTS", "1")); err == nil && n >= 0 {
+		maxConcurrentDeployments = n
+	}
+
+	// Guarantors are tracked but not required unless configured
+	minVerifiedGuarantors := 0
+	if n, err := strconv.Atoi(getEnv("MIN_VERIFIED_GUARANTORS", "0")); err == nil && n >= 0 {
+		minVerifiedGuarantors = n
+	}
+
+	deploymentDefaultDays := 90
+	if n, err := strconv.Atoi(getEnv("DEPLOYMENT_DEFAULT_DAYS", "90")); err == nil && n > 0 {
+		deploymentDefaultDays = n
+	}
+
+	deploymentStatusInterval := time.Hour
+	if d, err := time.ParseDuration(getEnv("DEPLOYMENT_STATUS_INTERVAL", "1h")); err == nil && d > 0 {
+		deploymentStatusInterval = d
+	}
+
 	config := &Config{
 		DBHost:        getEnv("DB_HOST", "localhost"),
 		DBPort:        getEnv("DB_PORT", "5432"),
@@ -45,6 +142,29 @@ func LoadConfig() (*Config, error) {
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 		RedisDB:       redisDB,
+
+		WorkerConcurrency:  workerConcurrency,
+		WorkerPollInterval: workerPollInterval,
+
+		AllocationStrategy: allocationStrategy,
+
+		PenaltyEvaluationInterval: penaltyEvaluationInterval,
+
+		PaymentImportColumns:    getEnv("PAYMENT_IMPORT_COLUMNS", ""),
+		PaymentImportDateLayout: getEnv("PAYMENT_IMPORT_DATE_LAYOUT", ""),
+
+		WebhookSecrets:   webhookSecrets,
+		WebhookTolerance: webhookTolerance,
+
+		AdminAPIKeys: adminAPIKeys,
+
+		MaxConcurrentDeployments: maxConcurrentDeployments,
+		MaxOutstandingExposure:   getEnv("MAX_OUTSTANDING_EXPOSURE", ""),
+		MinDeploymentDeposit:     getEnv("MIN_DEPLOYMENT_DEPOSIT", ""),
+		MinVerifiedGuarantors:    minVerifiedGuarantors,
+
+		DeploymentDefaultDays:    deploymentDefaultDays,
+		DeploymentStatusInterval: deploymentStatusInterval,
 	}
 
 	return config, nil
@@ -64,6 +184,27 @@ func (c *Config) GetDBURL() string {
 	)
 }
 
+// parseSecrets parses the comma separated name:secret pairs of the env
+// variable key; format describes a pair in errors. Names are lowercased.
+func parseSecrets(key, format, value string) (map[string]string, error) {
+	secrets := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return secrets, nil
+	}
+
+	for i, pair := range strings.Split(value, ",") {
+		name, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || secret == "" {
+			// The entry itself is left out of the error so secrets are not logged
+			return nil, fmt.Errorf("invalid %s entry %d: must be %s", key, i+1, format)
+		}
+		secrets[name] = secret
+	}
+
+	return secrets, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
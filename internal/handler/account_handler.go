@@ -3,7 +3,11 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
 	"github.com/emmrys-jay/gigmile/internal/service"
 	"github.com/emmrys-jay/gigmile/internal/utils"
 	"github.com/gorilla/mux"
@@ -29,12 +33,106 @@ func (h *AccountHandler) GetAccountByCustomer(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	account, err := h.accountService.GetAccountByCustomer(id)
-	if err != nil {
+	// Optional ?as_of= returns the balance at a past instant
+	var asOf *time.Time
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		parsed, err := parseAsOf(value)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		asOf = &parsed
+	}
+
+	account, err := h.accountService.GetAccountByCustomer(id, asOf)
+	if errors.Is(err, repository.ErrNotFound) {
 		respondWithError(w, r, http.StatusNotFound, err)
 		return
 	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
 
 	respondWithJSON(w, r, http.StatusOK, account)
 }
 
+func (h *AccountHandler) GetBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse customer ID (handles both GIG prefix and numeric formats)
+	id, err := utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid customer ID"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	// Defaults: the last 30 days, one point per day
+	to := time.Now().UTC()
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(models.DateLayout, value); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid to, expected YYYY-MM-DD"))
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(models.DateLayout, value); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid from, expected YYYY-MM-DD"))
+			return
+		}
+	}
+
+	interval := models.BalanceIntervalDay
+	if value := query.Get("interval"); value != "" {
+		interval = models.BalanceInterval(strings.ToLower(value))
+	}
+
+	history, err := h.accountService.GetBalanceHistory(id, from, to, interval)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, history)
+}
+
+// parseAsOf accepts an RFC 3339 instant, or a YYYY-MM-DD date meaning the end of that day (UTC)
+func parseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	date, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid as_of, expected RFC 3339 timestamp or YYYY-MM-DD")
+	}
+
+	return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+func (h *AccountHandler) GetLedgerByCustomer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse customer ID (handles both GIG prefix and numeric formats)
+	id, err := utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid customer ID"))
+		return
+	}
+
+	entries, err := h.accountService.GetLedgerByCustomer(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, entries)
+}
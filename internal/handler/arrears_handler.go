@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/service"
+)
+
+type ArrearsHandler struct {
+	arrearsService service.ArrearsService
+}
+
+func NewArrearsHandler(arrearsService service.ArrearsService) *ArrearsHandler {
+	return &ArrearsHandler{
+		arrearsService: arrearsService,
+	}
+}
+
+func (h *ArrearsHandler) GetArrears(w http.ResponseWriter, r *http.Request) {
+	// Optional filter: ?bucket=8-30
+	var bucket *models.DelinquencyBucket
+	if value := r.URL.Query().Get("bucket"); value != "" {
+		b := models.DelinquencyBucket(strings.ToUpper(value))
+		if !b.IsValid() {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid bucket, expected CURRENT, 1-7, 8-30, 31-90 or 90+"))
+			return
+		}
+		bucket = &b
+	}
+
+	arrears, err := h.arrearsService.GetArrears(bucket)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, arrears)
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type AssetHandler struct {
+	assetService service.AssetService
+	validator    *validator.Validate
+}
+
+func NewAssetHandler(assetService service.AssetService) *AssetHandler {
+	return &AssetHandler{
+		assetService: assetService,
+		validator:    validator.New(),
+	}
+}
+
+func (h *AssetHandler) CreateAsset(w http.ResponseWriter, r *http.Request) {
+	var assetReq models.CreateAssetRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&assetReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(assetReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := h.assetService.CreateAsset(&assetReq)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, asset)
+}
+
+func (h *AssetHandler) GetAssetByID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse asset ID (handles both AST prefix and numeric formats)
+	id, err := utils.ParseAssetID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid asset ID"))
+		return
+	}
+
+	asset, err := h.assetService.GetAssetByID(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, asset)
+}
+
+func (h *AssetHandler) GetAllAssets(w http.ResponseWriter, r *http.Request) {
+	// Optional filter: ?status=IN_STOCK
+	var status *models.AssetStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		filter := models.AssetStatus(strings.ToUpper(value))
+		if !filter.IsValid() {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid status, expected IN_STOCK, DEPLOYED, IN_REPAIR, REPOSSESSED or RETIRED"))
+			return
+		}
+		status = &filter
+	}
+
+	assets, err := h.assetService.GetAllAssets(status)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, assets)
+}
+
+func (h *AssetHandler) GetAssetDeployments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse asset ID (handles both AST prefix and numeric formats)
+	id, err := utils.ParseAssetID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid asset ID"))
+		return
+	}
+
+	deployments, err := h.assetService.GetDeployments(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, deployments)
+}
+
+func (h *AssetHandler) UpdateAsset(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse asset ID (handles both AST prefix and numeric formats)
+	id, err := utils.ParseAssetID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid asset ID"))
+		return
+	}
+
+	var assetReq models.UpdateAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&assetReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(assetReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := h.assetService.UpdateAsset(id, &assetReq)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	case errors.Is(err, service.ErrAssetInUse):
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, asset)
+}
+
+func (h *AssetHandler) DeleteAsset(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse asset ID (handles both AST prefix and numeric formats)
+	id, err := utils.ParseAssetID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid asset ID"))
+		return
+	}
+
+	err = h.assetService.DeleteAsset(id)
+	switch {
+	case errors.Is(err, service.ErrAssetInUse):
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, nil)
+}
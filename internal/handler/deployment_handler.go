@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
 	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/utils"
 	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
 )
 
 type DeploymentHandler struct {
@@ -36,11 +40,162 @@ func (h *DeploymentHandler) RecordDeployment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err := h.deploymentService.RecordDeployment(&req)
+	schedule, err := h.deploymentService.RecordDeployment(&req)
+
+	// Tell the caller every eligibility rule the request broke
+	var rejection *models.EligibilityRejection
+	if errors.As(err, &rejection) {
+		respondWithErrorData(w, r, http.StatusUnprocessableEntity, err, rejection)
+		return
+	}
+	if errors.Is(err, service.ErrAssetUnavailable) {
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		respondWithError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	respondWithJSON(w, r, http.StatusOK, nil)
+	respondWithJSON(w, r, http.StatusOK, schedule)
+}
+
+func (h *DeploymentHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Deployments are identified by their DEP ID. Any other ID is taken to be
+	// the deployment's transaction (TRX prefix optional), which is how they were
+	// identified before they had IDs of their own.
+	var schedule *models.RepaymentSchedule
+	if strings.HasPrefix(vars["id"], utils.DEPPrefix) {
+		id, err := utils.ParseDeploymentID(vars["id"])
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid deployment ID"))
+			return
+		}
+
+		schedule, err = h.deploymentService.GetSchedule(id)
+		if err != nil {
+			respondWithError(w, r, http.StatusNotFound, err)
+			return
+		}
+	} else {
+		id, err := utils.ParseTransactionID(vars["id"])
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid deployment ID"))
+			return
+		}
+
+		schedule, err = h.deploymentService.GetScheduleByTransaction(id)
+		if err != nil {
+			respondWithError(w, r, http.StatusNotFound, err)
+			return
+		}
+	}
+
+	respondWithJSON(w, r, http.StatusOK, schedule)
+}
+
+func (h *DeploymentHandler) GetAllDeployments(w http.ResponseWriter, r *http.Request) {
+	// Optional filter: ?status=DEFAULTED
+	var status *models.DeploymentStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		filter := models.DeploymentStatus(strings.ToUpper(value))
+		if !filter.IsValid() {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid status, expected ACTIVE, COMPLETED, DEFAULTED, REPOSSESSED or CANCELLED"))
+			return
+		}
+		status = &filter
+	}
+
+	deployments, err := h.deploymentService.GetAll(status)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, deployments)
+}
+
+func (h *DeploymentHandler) GetDeploymentByID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse deployment ID (handles both DEP prefix and numeric formats)
+	id, err := utils.ParseDeploymentID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid deployment ID"))
+		return
+	}
+
+	deployment, err := h.deploymentService.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, deployment)
+}
+
+func (h *DeploymentHandler) GetDeploymentsByCustomer(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse customer ID (handles both GIG prefix and numeric formats)
+	id, err := utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid customer ID"))
+		return
+	}
+
+	deployments, err := h.deploymentService.GetByCustomerID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, deployments)
+}
+
+func (h *DeploymentHandler) RepossessDeployment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := utils.ParseDeploymentID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid deployment ID"))
+		return
+	}
+
+	var req models.RepossessDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	deployment, err := h.deploymentService.Repossess(id, &req)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	case errors.Is(err, service.ErrInvalidDeploymentTransition):
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, deployment)
 }
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type GuarantorHandler struct {
+	guarantorService service.GuarantorService
+	validator        *validator.Validate
+}
+
+func NewGuarantorHandler(guarantorService service.GuarantorService) *GuarantorHandler {
+	return &GuarantorHandler{
+		guarantorService: guarantorService,
+		validator:        validator.New(),
+	}
+}
+
+func (h *GuarantorHandler) CreateGuarantor(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse customer ID (handles both GIG prefix and numeric formats)
+	customerID, err := utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid customer ID"))
+		return
+	}
+
+	var req models.CreateGuarantorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	guarantor, err := h.guarantorService.CreateGuarantor(customerID, &req)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	case errors.Is(err, service.ErrGuarantorLimit):
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, guarantor)
+}
+
+func (h *GuarantorHandler) GetGuarantors(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse customer ID (handles both GIG prefix and numeric formats)
+	customerID, err := utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid customer ID"))
+		return
+	}
+
+	guarantors, err := h.guarantorService.GetGuarantors(customerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, guarantors)
+}
+
+func (h *GuarantorHandler) GetGuarantor(w http.ResponseWriter, r *http.Request) {
+	customerID, id, err := parseGuarantorPath(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	guarantor, err := h.guarantorService.GetGuarantor(customerID, id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, guarantor)
+}
+
+func (h *GuarantorHandler) UpdateGuarantor(w http.ResponseWriter, r *http.Request) {
+	customerID, id, err := parseGuarantorPath(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req models.UpdateGuarantorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	guarantor, err := h.guarantorService.UpdateGuarantor(customerID, id, &req)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, guarantor)
+}
+
+func (h *GuarantorHandler) VerifyGuarantor(w http.ResponseWriter, r *http.Request) {
+	customerID, id, err := parseGuarantorPath(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req models.VerifyGuarantorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	guarantor, err := h.guarantorService.VerifyGuarantor(customerID, id, &req)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, guarantor)
+}
+
+func (h *GuarantorHandler) DeleteGuarantor(w http.ResponseWriter, r *http.Request) {
+	customerID, id, err := parseGuarantorPath(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.guarantorService.DeleteGuarantor(customerID, id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, nil)
+}
+
+// parseGuarantorPath reads the customer and guarantor IDs from
+// /customers/{id}/guarantors/{guarantor_id}
+func parseGuarantorPath(r *http.Request) (customerID, id int64, err error) {
+	vars := mux.Vars(r)
+
+	customerID, err = utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		return 0, 0, errors.New("invalid customer ID")
+	}
+
+	id, err = strconv.ParseInt(vars["guarantor_id"], 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid guarantor ID")
+	}
+
+	return customerID, id, nil
+}
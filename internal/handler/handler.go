@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"time"
@@ -47,11 +48,20 @@ func respondWithJSON(w http.ResponseWriter, r *http.Request, code int, data inte
 }
 
 func respondWithError(w http.ResponseWriter, r *http.Request, code int, err error) {
+	respondWithErrorData(w, r, code, err, nil)
+}
+
+// respondWithErrorData is respondWithError with details about the error in data
+func respondWithErrorData(w http.ResponseWriter, r *http.Request, code int, err error, data interface{}) {
 	start := middleware.GetStartTime(r)
 
+	if data == nil {
+		data = struct{}{}
+	}
+
 	response := ResponseFormat{
 		Status:  false,
-		Data:    struct{}{},
+		Data:    data,
 		Error:   err.Error(),
 		Message: "",
 	}
@@ -72,3 +82,17 @@ func respondWithError(w http.ResponseWriter, r *http.Request, code int, err erro
 	duration := time.Since(start)
 	log.Printf("[%s] %s %s - %d - %v", r.Method, r.URL.Path, r.RemoteAddr, code, duration)
 }
+
+// respondWithFile sends body as a downloadable file
+func respondWithFile(w http.ResponseWriter, r *http.Request, contentType, filename string, body []byte) {
+	start := middleware.GetStartTime(r)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+
+	// Log request
+	duration := time.Since(start)
+	log.Printf("[%s] %s %s - %d - %v", r.Method, r.URL.Path, r.RemoteAddr, http.StatusOK, duration)
+}
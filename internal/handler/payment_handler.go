@@ -1,11 +1,12 @@
 package handler
 
 import (
-	"encoding/json/v2"
 	"errors"
+	"io"
 	"log"
 	"net/http"
 
+	"github.com/emmrys-jay/gigmile/internal/middleware"
 	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/emmrys-jay/gigmile/internal/service"
 	"github.com/go-playground/validator/v10"
@@ -23,27 +24,91 @@ func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
 	}
 }
 
+// ProcessPaymentNotification handles /payments/notify and
+// /payments/notify/{provider}. The body is parsed as the provider whose
+// signature the webhook middleware verified, so a request signed the way one
+// provider signs cannot be read as another provider's payload.
 func (h *PaymentHandler) ProcessPaymentNotification(w http.ResponseWriter, r *http.Request) {
-	var req models.PaymentNotificationRequest
+	provider := middleware.GetWebhookProvider(r)
 
-	if err := json.UnmarshalRead(r.Body, &req); err != nil {
-		log.Printf("Error: %v", err)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
 		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
 		return
 	}
 
+	req, err := h.paymentService.ParseProviderNotification(provider, body)
+	if errors.Is(err, service.ErrUnknownProvider) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Error: %v", err)
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
 	// Validate request
-	err := h.validator.Struct(req)
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.paymentService.ProcessPaymentNotification(req)
+	if errors.Is(err, service.ErrInvalidTransition) {
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		respondWithError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	err = h.paymentService.ProcessPaymentNotification(&req)
+	// Let the provider know this reference was already received
+	if result.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
+	respondWithJSON(w, r, http.StatusOK, result)
+}
+
+// maxImportFileSize caps the size of an uploaded settlement file
+const maxImportFileSize = 10 << 20
+
+// ImportPayments imports a CSV settlement file sent as the multipart field
+// "file". The optional "provider" field names the provider that settled the
+// payments, and "columns" and "date_layout" override the configured column
+// mapping for this file.
+func (h *PaymentHandler) ImportPayments(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid multipart form or file larger than 10MB"))
+		return
+	}
+
+	file, _, err := r.FormFile("file")
 	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("file is required"))
+		return
+	}
+	defer file.Close()
+
+	overrides, err := models.ParsePaymentImportMapping(r.FormValue("columns"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	overrides.DateLayout = r.FormValue("date_layout")
+
+	report, err := h.paymentService.ImportPayments(file, r.FormValue("provider"), overrides)
+	if errors.Is(err, service.ErrInvalidImportFile) || errors.Is(err, service.ErrUnknownProvider) {
 		respondWithError(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
 
-	respondWithJSON(w, r, http.StatusOK, nil)
+	respondWithJSON(w, r, http.StatusOK, report)
 }
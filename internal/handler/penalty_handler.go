@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type PenaltyHandler struct {
+	penaltyService service.PenaltyService
+	validator      *validator.Validate
+}
+
+func NewPenaltyHandler(penaltyService service.PenaltyService) *PenaltyHandler {
+	return &PenaltyHandler{
+		penaltyService: penaltyService,
+		validator:      validator.New(),
+	}
+}
+
+func (h *PenaltyHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var ruleReq models.CreatePenaltyRuleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&ruleReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(ruleReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	rule, err := h.penaltyService.CreateRule(&ruleReq)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, rule)
+}
+
+func (h *PenaltyHandler) GetRuleByID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid penalty rule ID"))
+		return
+	}
+
+	rule, err := h.penaltyService.GetRuleByID(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, rule)
+}
+
+func (h *PenaltyHandler) GetAllRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.penaltyService.GetAllRules()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, rules)
+}
+
+func (h *PenaltyHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid penalty rule ID"))
+		return
+	}
+
+	var ruleReq models.UpdatePenaltyRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&ruleReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(ruleReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	rule, err := h.penaltyService.UpdateRule(id, &ruleReq)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, rule)
+}
+
+func (h *PenaltyHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid penalty rule ID"))
+		return
+	}
+
+	err = h.penaltyService.DeleteRule(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, nil)
+}
+
+// EvaluatePenalties runs the penalty evaluator on demand, for today or ?date=YYYY-MM-DD
+func (h *PenaltyHandler) EvaluatePenalties(w http.ResponseWriter, r *http.Request) {
+	date := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse(models.DateLayout, value)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid date, expected YYYY-MM-DD"))
+			return
+		}
+		date = parsed
+	}
+
+	backfill := false
+	if value := r.URL.Query().Get("backfill"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid backfill, expected true or false"))
+			return
+		}
+		backfill = parsed
+	}
+
+	evaluation, err := h.penaltyService.EvaluatePenalties(date, backfill)
+	if errors.Is(err, service.ErrFutureEvaluationDate) || errors.Is(err, service.ErrPastEvaluationDate) {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, evaluation)
+}
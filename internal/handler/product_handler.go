@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type ProductHandler struct {
+	productService service.ProductService
+	validator      *validator.Validate
+}
+
+func NewProductHandler(productService service.ProductService) *ProductHandler {
+	return &ProductHandler{
+		productService: productService,
+		validator:      validator.New(),
+	}
+}
+
+func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var productReq models.CreateProductRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&productReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(productReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	product, err := h.productService.CreateProduct(&productReq)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, product)
+}
+
+func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse product ID (handles both PRD prefix and numeric formats)
+	id, err := utils.ParseProductID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid product ID"))
+		return
+	}
+
+	product, err := h.productService.GetProductByID(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, product)
+}
+
+func (h *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := h.productService.GetAllProducts()
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, products)
+}
+
+func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse product ID (handles both PRD prefix and numeric formats)
+	id, err := utils.ParseProductID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid product ID"))
+		return
+	}
+
+	var productReq models.UpdateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&productReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(productReq); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(id, &productReq)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, product)
+}
+
+func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse product ID (handles both PRD prefix and numeric formats)
+	id, err := utils.ParseProductID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid product ID"))
+		return
+	}
+
+	err = h.productService.DeleteProduct(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, nil)
+}
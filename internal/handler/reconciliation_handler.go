@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/emmrys-jay/gigmile/internal/service"
+)
+
+type ReconciliationHandler struct {
+	reconciliationService service.ReconciliationService
+}
+
+func NewReconciliationHandler(reconciliationService service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// Reconcile reports balance mismatches. Adjustments are only written with ?dry_run=false.
+func (h *ReconciliationHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	dryRun := true
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid dry_run, expected true or false"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := h.reconciliationService.Reconcile(dryRun)
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, report)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/service"
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+func (h *ReportHandler) GetPortfolioReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// Defaults: as of today, covering the month to date
+	now := time.Now().UTC()
+	req := &models.PortfolioReportRequest{
+		AsOf:    time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		GroupBy: strings.ToLower(query.Get("group_by")),
+	}
+
+	dates := []struct {
+		name  string
+		value *time.Time
+	}{
+		{"as_of", &req.AsOf},
+		{"to", &req.To},
+		{"from", &req.From},
+	}
+
+	for _, d := range dates {
+		value := query.Get(d.name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(models.DateLayout, value)
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid "+d.name+", expected YYYY-MM-DD"))
+			return
+		}
+		*d.value = parsed
+	}
+
+	if req.To.IsZero() {
+		req.To = req.AsOf
+	}
+	if req.From.IsZero() {
+		req.From = time.Date(req.To.Year(), req.To.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	report, err := h.reportService.GetPortfolioReport(req)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, report)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+	"github.com/gorilla/mux"
+)
+
+type StatementHandler struct {
+	statementService service.StatementService
+}
+
+func NewStatementHandler(statementService service.StatementService) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+	}
+}
+
+func (h *StatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse customer ID (handles both GIG prefix and numeric formats)
+	id, err := utils.ParseCustomerID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid customer ID"))
+		return
+	}
+
+	query := r.URL.Query()
+
+	format := models.StatementFormatJSON
+	if value := query.Get("format"); value != "" {
+		format = models.StatementFormat(strings.ToLower(value))
+		if !format.IsValid() {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid format, expected json, csv or pdf"))
+			return
+		}
+	}
+
+	// Defaults: the month to date
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(models.DateLayout, value); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid to, expected YYYY-MM-DD"))
+			return
+		}
+	}
+
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(models.DateLayout, value); err != nil {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid from, expected YYYY-MM-DD"))
+			return
+		}
+	}
+
+	statement, err := h.statementService.GetStatement(id, from, to)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s-%s.%s",
+		utils.FormatCustomerID(id), from.Format("20060102"), to.Format("20060102"), format)
+
+	var body bytes.Buffer
+	switch format {
+	case models.StatementFormatCSV:
+		if err := service.WriteStatementCSV(&body, statement); err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		respondWithFile(w, r, "text/csv", filename, body.Bytes())
+
+	case models.StatementFormatPDF:
+		if err := service.WriteStatementPDF(&body, statement); err != nil {
+			respondWithError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		respondWithFile(w, r, "application/pdf", filename, body.Bytes())
+
+	default:
+		respondWithJSON(w, r, http.StatusOK, statement)
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/emmrys-jay/gigmile/internal/middleware"
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+)
+
+type SuspenseHandler struct {
+	suspenseService service.SuspenseService
+	validator       *validator.Validate
+}
+
+func NewSuspenseHandler(suspenseService service.SuspenseService) *SuspenseHandler {
+	return &SuspenseHandler{
+		suspenseService: suspenseService,
+		validator:       validator.New(),
+	}
+}
+
+func (h *SuspenseHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	payments, err := h.suspenseService.GetAll(r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, payments)
+}
+
+func (h *SuspenseHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid suspense payment ID"))
+		return
+	}
+
+	payment, err := h.suspenseService.GetByID(id)
+	if err != nil {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, payment)
+}
+
+func (h *SuspenseHandler) Match(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid suspense payment ID"))
+		return
+	}
+
+	var req models.MatchSuspenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+	req.Actor = middleware.GetAdminActor(r)
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	payment, err := h.suspenseService.Match(id, &req)
+	if err != nil {
+		respondWithSuspenseError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, payment)
+}
+
+func (h *SuspenseHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid suspense payment ID"))
+		return
+	}
+
+	var req models.RefundSuspenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+	req.Actor = middleware.GetAdminActor(r)
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	payment, err := h.suspenseService.Refund(id, &req)
+	if err != nil {
+		respondWithSuspenseError(w, r, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, payment)
+}
+
+func respondWithSuspenseError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondWithError(w, r, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrSuspenseResolved), errors.Is(err, service.ErrInvalidTransition):
+		respondWithError(w, r, http.StatusConflict, err)
+	default:
+		respondWithError(w, r, http.StatusBadRequest, err)
+	}
+}
@@ -1,21 +1,28 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
 	"github.com/emmrys-jay/gigmile/internal/service"
 	"github.com/emmrys-jay/gigmile/internal/utils"
+	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 )
 
 type TransactionHandler struct {
 	transactionService service.TransactionService
+	validator          *validator.Validate
 }
 
 func NewTransactionHandler(transactionService service.TransactionService) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: transactionService,
+		validator:          validator.New(),
 	}
 }
 
@@ -29,7 +36,29 @@ func (h *TransactionHandler) GetTransactionsByCustomer(w http.ResponseWriter, r
 		return
 	}
 
-	transactions, err := h.transactionService.GetTransactionsByCustomer(id)
+	// Optional filters: ?type=PAYMENT&direction=CREDIT
+	filter := &models.TransactionFilter{}
+	query := r.URL.Query()
+
+	if value := query.Get("type"); value != "" {
+		transactionType := models.TransactionType(strings.ToUpper(value))
+		if !transactionType.IsValid() {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid transaction type"))
+			return
+		}
+		filter.Type = &transactionType
+	}
+
+	if value := query.Get("direction"); value != "" {
+		direction := models.Direction(strings.ToUpper(value))
+		if !direction.IsValid() {
+			respondWithError(w, r, http.StatusBadRequest, errors.New("invalid direction, expected DEBIT or CREDIT"))
+			return
+		}
+		filter.Direction = &direction
+	}
+
+	transactions, err := h.transactionService.GetTransactionsByCustomer(id, filter)
 	if err != nil {
 		respondWithError(w, r, http.StatusInternalServerError, err)
 		return
@@ -37,3 +66,67 @@ func (h *TransactionHandler) GetTransactionsByCustomer(w http.ResponseWriter, r
 
 	respondWithJSON(w, r, http.StatusOK, transactions)
 }
+
+func (h *TransactionHandler) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse transaction ID (handles both TRX prefix and numeric formats)
+	id, err := utils.ParseTransactionID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid transaction ID"))
+		return
+	}
+
+	var req models.ReverseTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid request payload"))
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	reversal, err := h.transactionService.ReverseTransaction(id, &req)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	case errors.Is(err, service.ErrAlreadyReversed):
+		respondWithError(w, r, http.StatusConflict, err)
+		return
+	case errors.Is(err, service.ErrNotReversible):
+		respondWithError(w, r, http.StatusUnprocessableEntity, err)
+		return
+	case err != nil:
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusCreated, reversal)
+}
+
+func (h *TransactionHandler) GetAllocations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Parse transaction ID (handles both TRX prefix and numeric formats)
+	id, err := utils.ParseTransactionID(vars["id"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, errors.New("invalid transaction ID"))
+		return
+	}
+
+	allocations, err := h.transactionService.GetAllocations(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondWithError(w, r, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, allocations)
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuth(t *testing.T) {
+	keys := map[string]string{
+		"ops@gigmile.com":     "ops-key",
+		"finance@gigmile.com": "finance-key",
+	}
+
+	tests := []struct {
+		name          string
+		keys          map[string]string
+		authorization string
+		want          int
+		actor         string
+	}{
+		{"ops key", keys, "Bearer ops-key", http.StatusOK, "ops@gigmile.com"},
+		{"finance key", keys, "Bearer finance-key", http.StatusOK, "finance@gigmile.com"},
+		{"unknown key", keys, "Bearer other-key", http.StatusUnauthorized, ""},
+		{"key without the bearer scheme", keys, "ops-key", http.StatusUnauthorized, ""},
+		{"empty bearer token", keys, "Bearer ", http.StatusUnauthorized, ""},
+		{"no authorization header", keys, "", http.StatusUnauthorized, ""},
+		{"no keys configured", nil, "Bearer ", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		var actor string
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor = GetAdminActor(r)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/reconcile", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		rec := httptest.NewRecorder()
+		NewAdminAuth(tt.keys).Middleware(next).ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if actor != tt.actor {
+			t.Errorf("%s: actor = %q, want %q", tt.name, actor, tt.actor)
+		}
+	}
+}
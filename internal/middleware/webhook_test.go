@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/service"
+	"github.com/gorilla/mux"
+)
+
+func TestWebhookVerifier(t *testing.T) {
+	schemes := map[string]SignatureScheme{}
+	for _, provider := range service.PaymentProviders() {
+		schemes[provider.Name()] = provider
+	}
+	secrets := map[string]string{
+		DefaultWebhookProvider: "default-secret",
+		"flutterwave":          "flutterwave-hash",
+	}
+	verifier := NewWebhookVerifier(secrets, schemes, 5*time.Minute)
+
+	body := []byte(`{"transaction_reference":"ref-1"}`)
+	sign := func(r *http.Request, secret string, at time.Time) {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		r.Header.Set(service.WebhookTimestampHeader, timestamp)
+		r.Header.Set(service.WebhookSignatureHeader, hex.EncodeToString(service.SignWebhook(secret, timestamp, body)))
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		prepare  func(r *http.Request)
+		want     int
+		provider string
+	}{
+		{
+			name:     "signed by the default provider",
+			path:     "/payments/notify",
+			prepare:  func(r *http.Request) { sign(r, "default-secret", time.Now()) },
+			want:     http.StatusOK,
+			provider: DefaultWebhookProvider,
+		},
+		{
+			name: "provider named in the header",
+			path: "/payments/notify",
+			prepare: func(r *http.Request) {
+				r.Header.Set(WebhookProviderHeader, "Flutterwave")
+				r.Header.Set(service.FlutterwaveSignatureHeader, "flutterwave-hash")
+			},
+			want:     http.StatusOK,
+			provider: "flutterwave",
+		},
+		{
+			name:     "provider named in the route",
+			path:     "/payments/notify/flutterwave",
+			prepare:  func(r *http.Request) { r.Header.Set(service.FlutterwaveSignatureHeader, "flutterwave-hash") },
+			want:     http.StatusOK,
+			provider: "flutterwave",
+		},
+		{
+			name:    "bad signature",
+			path:    "/payments/notify",
+			prepare: func(r *http.Request) { sign(r, "wrong-secret", time.Now()) },
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "missing signature",
+			path:    "/payments/notify",
+			prepare: func(r *http.Request) {},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "skewed timestamp",
+			path:    "/payments/notify",
+			prepare: func(r *http.Request) { sign(r, "default-secret", time.Now().Add(-10*time.Minute)) },
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "timestamp in the future",
+			path:    "/payments/notify",
+			prepare: func(r *http.Request) { sign(r, "default-secret", time.Now().Add(10*time.Minute)) },
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "provider without an adapter",
+			path:    "/payments/notify/unknown",
+			prepare: func(r *http.Request) { sign(r, "default-secret", time.Now()) },
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "provider without a secret",
+			path:    "/payments/notify/paystack",
+			prepare: func(r *http.Request) {},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name: "route provider takes precedence over the header",
+			path: "/payments/notify/flutterwave",
+			prepare: func(r *http.Request) {
+				r.Header.Set(WebhookProviderHeader, DefaultWebhookProvider)
+				sign(r, "default-secret", time.Now())
+			},
+			want: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		var received []byte
+		var provider string
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			provider = GetWebhookProvider(r)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		router := mux.NewRouter()
+		router.Handle("/payments/notify", verifier.Middleware(next))
+		router.Handle("/payments/notify/{provider}", verifier.Middleware(next))
+
+		req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body))
+		tt.prepare(req)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+			continue
+		}
+		if tt.want == http.StatusOK && !bytes.Equal(received, body) {
+			t.Errorf("%s: handler received body %q, want %q", tt.name, received, body)
+		}
+		if tt.want == http.StatusOK && provider != tt.provider {
+			t.Errorf("%s: handler saw provider %q, want %q", tt.name, provider, tt.provider)
+		}
+	}
+}
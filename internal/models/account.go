@@ -8,11 +8,13 @@ import (
 )
 
 type Account struct {
-	ID         int64     `json:"id"`
-	CustomerID int64     `json:"customer_id"`
-	Balance    float64   `json:"balance"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         int64      `json:"id"`
+	CustomerID int64      `json:"customer_id"`
+	Balance    Money      `json:"balance"`
+	Arrears    *Arrears   `json:"arrears,omitempty"` // Set on the customer account endpoint only
+	AsOf       *time.Time `json:"as_of,omitempty"`   // Set when Balance is a historical balance
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 // MarshalJSON customizes JSON marshaling to include formatted account_id and customer_id
@@ -31,10 +33,10 @@ func (a *Account) MarshalJSON() ([]byte, error) {
 }
 
 type CreateAccountRequest struct {
-	CustomerID int64   `json:"customer_id"`
-	Balance    float64 `json:"balance"`
+	CustomerID int64 `json:"customer_id"`
+	Balance    Money `json:"balance"`
 }
 
 type UpdateAccountRequest struct {
-	Balance *float64 `json:"balance,omitempty"`
+	Balance *Money `json:"balance,omitempty"`
 }
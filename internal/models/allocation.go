@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// AllocationStrategy decides the order in which open installments are paid
+type AllocationStrategy string
+
+const (
+	// AllocationStrategyFIFO pays the installment with the earliest due date first
+	AllocationStrategyFIFO AllocationStrategy = "FIFO"
+	// AllocationStrategyLIFO pays the installment with the latest due date first
+	AllocationStrategyLIFO AllocationStrategy = "LIFO"
+)
+
+// IsValid reports whether s is a supported allocation strategy
+func (s AllocationStrategy) IsValid() bool {
+	switch s {
+	case AllocationStrategyFIFO, AllocationStrategyLIFO:
+		return true
+	}
+	return false
+}
+
+// AllocationComponent is the part of an installment an allocation paid
+type AllocationComponent string
+
+const (
+	AllocationComponentPenalty   AllocationComponent = "PENALTY"
+	AllocationComponentFee       AllocationComponent = "FEE"
+	AllocationComponentPrincipal AllocationComponent = "PRINCIPAL"
+)
+
+// PaymentAllocation records how much of a payment went to one component of an installment
+type PaymentAllocation struct {
+	ID            int64               `json:"id"`
+	TransactionID int64               `json:"-"`
+	ScheduleID    int64               `json:"schedule_id"`
+	InstallmentID int64               `json:"installment_id"`
+	Component     AllocationComponent `json:"component"`
+	Amount        Money               `json:"amount"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted transaction_id
+func (a *PaymentAllocation) MarshalJSON() ([]byte, error) {
+	type Alias PaymentAllocation
+
+	return json.Marshal(struct {
+		TransactionID string `json:"transaction_id"`
+		Alias
+	}{
+		TransactionID: utils.FormatTransactionID(a.TransactionID),
+		Alias:         (Alias)(*a),
+	})
+}
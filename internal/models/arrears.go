@@ -0,0 +1,99 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// DelinquencyBucket groups customers in arrears by days past due
+type DelinquencyBucket string
+
+const (
+	DelinquencyBucketCurrent DelinquencyBucket = "CURRENT"
+	DelinquencyBucket1To7    DelinquencyBucket = "1-7"
+	DelinquencyBucket8To30   DelinquencyBucket = "8-30"
+	DelinquencyBucket31To90  DelinquencyBucket = "31-90"
+	DelinquencyBucketOver90  DelinquencyBucket = "90+"
+)
+
+// IsValid reports whether b is a supported delinquency bucket
+func (b DelinquencyBucket) IsValid() bool {
+	switch b {
+	case DelinquencyBucketCurrent, DelinquencyBucket1To7, DelinquencyBucket8To30, DelinquencyBucket31To90, DelinquencyBucketOver90:
+		return true
+	}
+	return false
+}
+
+// BucketForDaysPastDue returns the delinquency bucket for a number of days past due
+func BucketForDaysPastDue(days int) DelinquencyBucket {
+	switch {
+	case days <= 0:
+		return DelinquencyBucketCurrent
+	case days <= 7:
+		return DelinquencyBucket1To7
+	case days <= 30:
+		return DelinquencyBucket8To30
+	case days <= 90:
+		return DelinquencyBucket31To90
+	default:
+		return DelinquencyBucketOver90
+	}
+}
+
+// Arrears is what a customer owes on installments that are past their due date
+type Arrears struct {
+	CustomerID          int64             `json:"-"`
+	AccountID           int64             `json:"-"`
+	Amount              Money             `json:"amount"`
+	DaysPastDue         int               `json:"days_past_due"`
+	Bucket              DelinquencyBucket `json:"bucket"`
+	OverdueInstallments int               `json:"overdue_installments"`
+	OldestDueDate       *time.Time        `json:"-"`
+	AsOf                time.Time         `json:"-"`
+}
+
+// NewArrears derives days past due and the bucket from the oldest unpaid due date
+func NewArrears(customerID, accountID int64, amount Money, overdue int, oldestDueDate *time.Time, asOf time.Time) *Arrears {
+	arrears := &Arrears{
+		CustomerID:          customerID,
+		AccountID:           accountID,
+		Amount:              amount,
+		OverdueInstallments: overdue,
+		OldestDueDate:       oldestDueDate,
+		AsOf:                asOf,
+	}
+
+	if oldestDueDate != nil && amount.IsPositive() {
+		arrears.DaysPastDue = int(asOf.Sub(*oldestDueDate).Hours() / 24)
+	}
+	arrears.Bucket = BucketForDaysPastDue(arrears.DaysPastDue)
+
+	return arrears
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted IDs and dates
+func (a *Arrears) MarshalJSON() ([]byte, error) {
+	type Alias Arrears
+
+	var oldestDueDate string
+	if a.OldestDueDate != nil {
+		oldestDueDate = a.OldestDueDate.Format(DateLayout)
+	}
+
+	return json.Marshal(struct {
+		CustomerID    string `json:"customer_id"`
+		AccountID     string `json:"account_id"`
+		OldestDueDate string `json:"oldest_due_date,omitempty"`
+		AsOf          string `json:"as_of"`
+		Alias
+	}{
+		CustomerID:    utils.FormatCustomerID(a.CustomerID),
+		AccountID:     utils.FormatAccountID(a.AccountID),
+		OldestDueDate: oldestDueDate,
+		AsOf:          a.AsOf.Format(DateLayout),
+		Alias:         (Alias)(*a),
+	})
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type AssetStatus string
+
+const (
+	AssetStatusInStock     AssetStatus = "IN_STOCK"
+	AssetStatusDeployed    AssetStatus = "DEPLOYED"
+	AssetStatusInRepair    AssetStatus = "IN_REPAIR"
+	AssetStatusRepossessed AssetStatus = "REPOSSESSED"
+	AssetStatusRetired     AssetStatus = "RETIRED"
+)
+
+// IsValid reports whether s is a known asset status
+func (s AssetStatus) IsValid() bool {
+	switch s {
+	case AssetStatusInStock, AssetStatusDeployed, AssetStatusInRepair, AssetStatusRepossessed, AssetStatusRetired:
+		return true
+	}
+	return false
+}
+
+// Asset is a physical vehicle that can be deployed to a customer. ProductID
+// is the catalog product it is sold as, if any.
+type Asset struct {
+	ID           int64       `json:"-"`
+	ProductID    *int64      `json:"-"`
+	VIN          string      `json:"vin"`
+	PlateNumber  *string     `json:"plate_number,omitempty"`
+	Make         string      `json:"make"`
+	Model        string      `json:"model"`
+	PurchaseCost Money       `json:"purchase_cost"`
+	Status       AssetStatus `json:"status"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	DeletedAt    *time.Time  `json:"deleted_at,omitempty"`
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted IDs
+func (a *Asset) MarshalJSON() ([]byte, error) {
+	type Alias Asset
+
+	var productID string
+	if a.ProductID != nil {
+		productID = utils.FormatProductID(*a.ProductID)
+	}
+
+	return json.Marshal(struct {
+		ID        string `json:"id"`
+		ProductID string `json:"product_id,omitempty"`
+		Alias
+	}{
+		ID:        utils.FormatAssetID(a.ID),
+		ProductID: productID,
+		Alias:     (Alias)(*a),
+	})
+}
+
+// CreateAssetRequest registers an asset, which starts IN_STOCK
+type CreateAssetRequest struct {
+	ProductID    string  `json:"product_id"`
+	VIN          string  `json:"vin" validate:"required,max=64"`
+	PlateNumber  *string `json:"plate_number,omitempty" validate:"omitempty,max=32"`
+	Make         string  `json:"make" validate:"required,max=100"`
+	Model        string  `json:"model" validate:"required,max=100"`
+	PurchaseCost Money   `json:"purchase_cost" validate:"gte=0"`
+}
+
+// UpdateAssetRequest changes an asset. DEPLOYED is set only by recording a
+// deployment, so it cannot be requested here.
+type UpdateAssetRequest struct {
+	ProductID    *string      `json:"product_id,omitempty"`
+	VIN          *string      `json:"vin,omitempty" validate:"omitempty,max=64"`
+	PlateNumber  *string      `json:"plate_number,omitempty" validate:"omitempty,max=32"`
+	Make         *string      `json:"make,omitempty" validate:"omitempty,max=100"`
+	Model        *string      `json:"model,omitempty" validate:"omitempty,max=100"`
+	PurchaseCost *Money       `json:"purchase_cost,omitempty" validate:"omitempty,gte=0"`
+	Status       *AssetStatus `json:"status,omitempty" validate:"omitempty,oneof=IN_STOCK IN_REPAIR REPOSSESSED RETIRED"`
+}
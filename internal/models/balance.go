@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// BalanceInterval is the spacing of the points in a balance history
+type BalanceInterval string
+
+const (
+	BalanceIntervalDay   BalanceInterval = "day"
+	BalanceIntervalWeek  BalanceInterval = "week"
+	BalanceIntervalMonth BalanceInterval = "month"
+)
+
+// IsValid reports whether i is a supported balance interval
+func (i BalanceInterval) IsValid() bool {
+	switch i {
+	case BalanceIntervalDay, BalanceIntervalWeek, BalanceIntervalMonth:
+		return true
+	}
+	return false
+}
+
+// BalancePoint is the closing balance of an account on a date
+type BalancePoint struct {
+	Date    time.Time `json:"-"`
+	Balance Money     `json:"balance"`
+}
+
+// MarshalJSON customizes JSON marshaling to format the date
+func (p *BalancePoint) MarshalJSON() ([]byte, error) {
+	type Alias BalancePoint
+
+	return json.Marshal(struct {
+		Date string `json:"date"`
+		Alias
+	}{
+		Date:  p.Date.Format(DateLayout),
+		Alias: (Alias)(*p),
+	})
+}
+
+type BalanceHistory struct {
+	CustomerID int64           `json:"-"`
+	AccountID  int64           `json:"-"`
+	From       time.Time       `json:"-"`
+	To         time.Time       `json:"-"`
+	Interval   BalanceInterval `json:"interval"`
+	Points     []*BalancePoint `json:"points"`
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted IDs and dates
+func (h *BalanceHistory) MarshalJSON() ([]byte, error) {
+	type Alias BalanceHistory
+
+	return json.Marshal(struct {
+		CustomerID string `json:"customer_id"`
+		AccountID  string `json:"account_id"`
+		From       string `json:"from"`
+		To         string `json:"to"`
+		Alias
+	}{
+		CustomerID: utils.FormatCustomerID(h.CustomerID),
+		AccountID:  utils.FormatAccountID(h.AccountID),
+		From:       h.From.Format(DateLayout),
+		To:         h.To.Format(DateLayout),
+		Alias:      (Alias)(*h),
+	})
+}
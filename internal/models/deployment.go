@@ -1,7 +1,122 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type DeploymentStatus string
+
+const (
+	DeploymentStatusActive      DeploymentStatus = "ACTIVE"
+	DeploymentStatusCompleted   DeploymentStatus = "COMPLETED"
+	DeploymentStatusDefaulted   DeploymentStatus = "DEFAULTED"
+	DeploymentStatusRepossessed DeploymentStatus = "REPOSSESSED"
+	DeploymentStatusCancelled   DeploymentStatus = "CANCELLED"
+)
+
+// deploymentStatusTransitions lists the statuses each status may move to.
+// COMPLETED, REPOSSESSED and CANCELLED are terminal.
+var deploymentStatusTransitions = map[DeploymentStatus][]DeploymentStatus{
+	DeploymentStatusActive:      {DeploymentStatusCompleted, DeploymentStatusDefaulted, DeploymentStatusRepossessed, DeploymentStatusCancelled},
+	DeploymentStatusDefaulted:   {DeploymentStatusActive, DeploymentStatusCompleted, DeploymentStatusRepossessed, DeploymentStatusCancelled},
+	DeploymentStatusCompleted:   {},
+	DeploymentStatusRepossessed: {},
+	DeploymentStatusCancelled:   {},
+}
+
+// IsValid reports whether s is a known deployment status
+func (s DeploymentStatus) IsValid() bool {
+	_, ok := deploymentStatusTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether a deployment in status s is closed
+func (s DeploymentStatus) IsTerminal() bool {
+	return len(deploymentStatusTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether a deployment in status s may move to next
+func (s DeploymentStatus) CanTransitionTo(next DeploymentStatus) bool {
+	for _, allowed := range deploymentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Deployment is an asset given to a customer on credit. TransactionID is the
+// DEPLOYMENT transaction that debited the customer's account.
+type Deployment struct {
+	ID            int64              `json:"-"`
+	CustomerID    int64              `json:"-"`
+	AccountID     int64              `json:"-"`
+	ProductID     *int64             `json:"-"`
+	AssetID       *int64             `json:"-"` // Empty for deployments recorded before the asset registry
+	TransactionID int64              `json:"-"`
+	Reference     string             `json:"reference"`
+	Amount        Money              `json:"amount"`
+	Status        DeploymentStatus   `json:"status"`
+	StartDate     time.Time          `json:"-"`
+	EndDate       time.Time          `json:"-"` // Due date of the last installment
+	ClosedAt      *time.Time         `json:"closed_at,omitempty"`
+	ClosedReason  *string            `json:"closed_reason,omitempty"`
+	Schedule      *RepaymentSchedule `json:"schedule,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted IDs and dates
+func (d *Deployment) MarshalJSON() ([]byte, error) {
+	type Alias Deployment
+
+	var productID string
+	if d.ProductID != nil {
+		productID = utils.FormatProductID(*d.ProductID)
+	}
+
+	var assetID string
+	if d.AssetID != nil {
+		assetID = utils.FormatAssetID(*d.AssetID)
+	}
+
+	return json.Marshal(struct {
+		ID            string `json:"id"`
+		CustomerID    string `json:"customer_id"`
+		AccountID     string `json:"account_id"`
+		ProductID     string `json:"product_id,omitempty"`
+		AssetID       string `json:"asset_id,omitempty"`
+		TransactionID string `json:"transaction_id"`
+		StartDate     string `json:"start_date"`
+		EndDate       string `json:"end_date"`
+		Alias
+	}{
+		ID:            utils.FormatDeploymentID(d.ID),
+		CustomerID:    utils.FormatCustomerID(d.CustomerID),
+		AccountID:     utils.FormatAccountID(d.AccountID),
+		ProductID:     productID,
+		AssetID:       assetID,
+		TransactionID: utils.FormatTransactionID(d.TransactionID),
+		StartDate:     d.StartDate.Format(DateLayout),
+		EndDate:       d.EndDate.Format(DateLayout),
+		Alias:         (Alias)(*d),
+	})
+}
+
+type RepossessDeploymentRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
 type CreateDeploymentRequest struct {
-	CustomerID  string `json:"customer_id" validate:"required"`
-	Reference   string `json:"reference" validate:"required"`
-	Description string `json:"description"`
+	CustomerID  string             `json:"customer_id" validate:"required"`
+	ProductID   string             `json:"product_id" validate:"required"`
+	AssetID     string             `json:"asset_id"` // Optional; must be IN_STOCK
+	Reference   string             `json:"reference" validate:"required"`
+	Description string             `json:"description"`
+	Tenor       int                `json:"tenor" validate:"omitempty,min=1,max=1000"`                 // Defaults to the product tenor
+	Frequency   RepaymentFrequency `json:"frequency" validate:"omitempty,oneof=DAILY WEEKLY MONTHLY"` // Defaults to the product frequency
+	StartDate   string             `json:"start_date" validate:"omitempty,datetime=2006-01-02"`       // First due date; defaults to one period after today
 }
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EligibilityPolicy holds the limits a customer must be within to receive a
+// deployment. A zero limit is not enforced.
+type EligibilityPolicy struct {
+	MaxConcurrentDeployments int
+	MaxOutstandingExposure   Money
+	MinDeposit               Money
+	MinVerifiedGuarantors    int
+}
+
+// EligibilityRule identifies the rule a deployment request broke
+type EligibilityRule string
+
+const (
+	EligibilityRuleCustomerDeleted          EligibilityRule = "CUSTOMER_DELETED"
+	EligibilityRuleCustomerInArrears        EligibilityRule = "CUSTOMER_IN_ARREARS"
+	EligibilityRuleMaxConcurrentDeployments EligibilityRule = "MAX_CONCURRENT_DEPLOYMENTS"
+	EligibilityRuleMaxOutstandingExposure   EligibilityRule = "MAX_OUTSTANDING_EXPOSURE"
+	EligibilityRuleMinDeposit               EligibilityRule = "MIN_DEPOSIT"
+	EligibilityRuleMinVerifiedGuarantors    EligibilityRule = "MIN_VERIFIED_GUARANTORS"
+)
+
+// Exposure is what a customer owes on their active repayment schedules
+type Exposure struct {
+	ActiveDeployments int
+	Outstanding       Money
+}
+
+// EligibilityCheck is what the eligibility rules are evaluated against
+type EligibilityCheck struct {
+	Customer *Customer
+	Account  *Account
+	Arrears  *Arrears
+	Exposure *Exposure
+	Amount   Money // Price of the requested deployment
+
+	VerifiedGuarantors int
+}
+
+// EligibilityViolation describes one broken rule. Limit and Actual are the
+// configured limit and the customer's value, for rules that have them.
+type EligibilityViolation struct {
+	Rule    EligibilityRule `json:"rule"`
+	Message string          `json:"message"`
+	Limit   any             `json:"limit,omitempty"`
+	Actual  any             `json:"actual,omitempty"`
+}
+
+// EligibilityRejection is returned when a deployment request breaks one or
+// more eligibility rules. Every broken rule is listed.
+type EligibilityRejection struct {
+	CustomerID int64                   `json:"-"`
+	Violations []*EligibilityViolation `json:"violations"`
+}
+
+func (r *EligibilityRejection) Error() string {
+	messages := make([]string, len(r.Violations))
+	for i, violation := range r.Violations {
+		messages[i] = violation.Message
+	}
+	return fmt.Sprintf("customer is not eligible for a deployment: %s", strings.Join(messages, "; "))
+}
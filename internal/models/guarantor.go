@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// Guarantor vouches for a customer. VerifiedAt is set once ops have confirmed
+// the guarantor, and cleared when their name or phone number changes.
+type Guarantor struct {
+	ID           int64      `json:"id"`
+	CustomerID   int64      `json:"-"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	PhoneNumber  string     `json:"phone_number"`
+	Email        *string    `json:"email,omitempty"`
+	Address      *string    `json:"address,omitempty"`
+	Relationship string     `json:"relationship"`
+	VerifiedAt   *time.Time `json:"verified_at,omitempty"`
+	VerifiedBy   *string    `json:"verified_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsVerified reports whether the guarantor has been verified
+func (g *Guarantor) IsVerified() bool {
+	return g.VerifiedAt != nil
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted customer_id
+// and whether the guarantor is verified
+func (g *Guarantor) MarshalJSON() ([]byte, error) {
+	type Alias Guarantor
+
+	return json.Marshal(struct {
+		CustomerID string `json:"customer_id"`
+		Verified   bool   `json:"verified"`
+		Alias
+	}{
+		CustomerID: utils.FormatCustomerID(g.CustomerID),
+		Verified:   g.IsVerified(),
+		Alias:      (Alias)(*g),
+	})
+}
+
+type CreateGuarantorRequest struct {
+	FirstName    string  `json:"first_name" validate:"required"`
+	LastName     string  `json:"last_name" validate:"required"`
+	PhoneNumber  string  `json:"phone_number" validate:"required,max=32"`
+	Email        *string `json:"email,omitempty" validate:"omitempty,email"`
+	Address      *string `json:"address,omitempty"`
+	Relationship string  `json:"relationship" validate:"required,max=50"` // e.g. SPOUSE, SIBLING, EMPLOYER
+}
+
+type UpdateGuarantorRequest struct {
+	FirstName    *string `json:"first_name,omitempty" validate:"omitempty"`
+	LastName     *string `json:"last_name,omitempty" validate:"omitempty"`
+	PhoneNumber  *string `json:"phone_number,omitempty" validate:"omitempty,max=32"`
+	Email        *string `json:"email,omitempty" validate:"omitempty,email"`
+	Address      *string `json:"address,omitempty"`
+	Relationship *string `json:"relationship,omitempty" validate:"omitempty,max=50"`
+}
+
+type VerifyGuarantorRequest struct {
+	Actor string `json:"actor" validate:"required"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "PENDING"
+	JobStatusRunning JobStatus = "RUNNING"
+	JobStatusDone    JobStatus = "DONE"
+	JobStatusDead    JobStatus = "DEAD"
+)
+
+// Job is a unit of background work stored in the jobs table
+type Job struct {
+	ID          int64      `json:"id"`
+	Queue       string     `json:"queue"`
+	Payload     []byte     `json:"payload"`
+	Status      JobStatus  `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	RunAt       time.Time  `json:"run_at"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+	LastError   *string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsLastAttempt reports whether a failure of the current attempt moves the job to DEAD
+func (j *Job) IsLastAttempt() bool {
+	return j.Attempts >= j.MaxAttempts
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type Direction string
+
+const (
+	DirectionDebit  Direction = "DEBIT"
+	DirectionCredit Direction = "CREDIT"
+)
+
+// IsValid reports whether d is DEBIT or CREDIT
+func (d Direction) IsValid() bool {
+	return d == DirectionDebit || d == DirectionCredit
+}
+
+// Opposite returns CREDIT for DEBIT and DEBIT for CREDIT
+func (d Direction) Opposite() Direction {
+	if d == DirectionDebit {
+		return DirectionCredit
+	}
+	return DirectionDebit
+}
+
+// LedgerEntry records a single posting against an account balance.
+// Every Debit or Credit on an account writes exactly one entry.
+type LedgerEntry struct {
+	ID            int64     `json:"id"`
+	AccountID     int64     `json:"-"`
+	TransactionID int64     `json:"-"`
+	Direction     Direction `json:"direction"`
+	Amount        Money     `json:"amount"`
+	BalanceBefore Money     `json:"balance_before"`
+	BalanceAfter  Money     `json:"balance_after"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted account_id and transaction_id
+func (e *LedgerEntry) MarshalJSON() ([]byte, error) {
+	type Alias LedgerEntry
+
+	return json.Marshal(struct {
+		AccountID     string `json:"account_id"`
+		TransactionID string `json:"transaction_id"`
+		Alias
+	}{
+		AccountID:     utils.FormatAccountID(e.AccountID),
+		TransactionID: utils.FormatTransactionID(e.TransactionID),
+		Alias:         (Alias)(*e),
+	})
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Money is an exact monetary amount stored in minor units (kobo), matching
+// the DECIMAL(15, 2) columns in the schema.
+//
+// Rounding rule: whenever a value carries more than two decimal places
+// (provider payloads, database arithmetic, percentages) it is rounded to the
+// nearest minor unit, with halves rounded away from zero.
+type Money int64
+
+// MoneyScale is the number of minor units in one major unit
+const MoneyScale = 100
+
+// ErrInvalidMoney is returned when an amount cannot be parsed
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// NewMoneyFromMinor creates a Money value from an amount in minor units
+func NewMoneyFromMinor(minor int64) Money {
+	return Money(minor)
+}
+
+// NewMoneyFromMajor creates a Money value from a whole amount in major units
+func NewMoneyFromMajor(major int64) Money {
+	return Money(major * MoneyScale)
+}
+
+// ParseMoney parses a decimal string such as "10000", "-25.5" or "99.995"
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidMoney
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, ErrInvalidMoney
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	// Keep two decimal places and round the rest half away from zero
+	roundUp := false
+	if len(fracPart) > 2 {
+		roundUp = fracPart[2] >= '5'
+		fracPart = fracPart[:2]
+	}
+	fracPart += strings.Repeat("0", 2-len(fracPart))
+
+	minor, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	if roundUp {
+		minor++
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return Money(minor), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor returns the amount in minor units
+func (m Money) Minor() int64 {
+	return int64(m)
+}
+
+// Abs returns the absolute value of the amount
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m > 0
+}
+
+// MulBasisPoints returns the amount multiplied by bps hundredths of a
+// percent, rounded to the nearest minor unit with halves away from zero
+func (m Money) MulBasisPoints(bps int64) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(bps))
+	quotient, remainder := new(big.Int).QuoRem(product, big.NewInt(10000), new(big.Int))
+
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(big.NewInt(10000)) >= 0 {
+		if product.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return Money(quotient.Int64())
+}
+
+// String formats the amount with exactly two decimal places, e.g. "-1000000.00"
+func (m Money) String() string {
+	sign := ""
+	minor := int64(m)
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, minor/MoneyScale, minor%MoneyScale)
+}
+
+// MarshalJSON encodes the amount as a JSON string to avoid float precision loss
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts both JSON strings ("100.50") and JSON numbers (100.50)
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMoney, data)
+		}
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner so DECIMAL columns scan exactly
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into Money")
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("cannot scan non-finite numeric into Money")
+	}
+
+	// value = Int * 10^Exp, so minor units = Int * 10^(Exp+2)
+	minor := new(big.Int).Set(v.Int)
+	shift := int64(v.Exp) + 2
+	if shift >= 0 {
+		minor.Mul(minor, new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil))
+	} else {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(-shift), nil)
+		remainder := new(big.Int)
+		minor.QuoRem(minor, divisor, remainder)
+
+		// Round half away from zero
+		remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+		if remainder.Cmp(divisor) >= 0 {
+			if v.Int.Sign() < 0 {
+				minor.Sub(minor, big.NewInt(1))
+			} else {
+				minor.Add(minor, big.NewInt(1))
+			}
+		}
+	}
+
+	if !minor.IsInt64() {
+		return fmt.Errorf("numeric value out of range for Money")
+	}
+
+	*m = Money(minor.Int64())
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer so Money encodes as an exact DECIMAL
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(m)), Exp: -2, Valid: true}, nil
+}
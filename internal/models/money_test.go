@@ -0,0 +1,194 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"10000", 1000000},
+		{"0", 0},
+		{"0.01", 1},
+		{".5", 50},
+		{"5.", 500},
+		{"-25.5", -2550},
+		{"+25.5", 2550},
+		{"  12.34  ", 1234},
+		{"99.994", 9999},
+		{"99.995", 10000},
+		{"0.005", 1},
+		{"0.0049", 0},
+		{"-0.005", -1},
+		{"-99.995", -10000},
+		{"-0.004", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if err != nil {
+			t.Errorf("ParseMoney(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	for _, in := range []string{"", " ", "-", ".", "abc", "1,000", "1.2.3", "--1", "1e5", "12.3a"} {
+		if _, err := ParseMoney(in); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("ParseMoney(%q) error = %v, want ErrInvalidMoney", in, err)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{-1, "-0.01"},
+		{1000000, "10000.00"},
+		{-2550, "-25.50"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{`"100.50"`, 10050},
+		{`100.50`, 10050},
+		{`"-0.01"`, -1},
+		{`7`, 700},
+		{`"99.995"`, 10000},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+
+		data, err := json.Marshal(got)
+		if err != nil {
+			t.Errorf("Marshal(%d) returned error: %v", got, err)
+			continue
+		}
+		var roundTrip Money
+		if err := json.Unmarshal(data, &roundTrip); err != nil || roundTrip != got {
+			t.Errorf("round trip of %d through %s = %d, %v", got, data, roundTrip, err)
+		}
+	}
+
+	data, err := json.Marshal(Money(-2550))
+	if err != nil || string(data) != `"-25.50"` {
+		t.Errorf("Marshal(-2550) = %s, %v, want \"-25.50\"", data, err)
+	}
+
+	for _, in := range []string{`"abc"`, `true`, `"1,000"`} {
+		var m Money
+		if err := json.Unmarshal([]byte(in), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %d, want error", in, m)
+		}
+	}
+}
+
+func TestMoneyNumeric(t *testing.T) {
+	tests := []struct {
+		name string
+		in   pgtype.Numeric
+		want Money
+	}{
+		{"two decimals", pgtype.Numeric{Int: big.NewInt(10050), Exp: -2, Valid: true}, 10050},
+		{"whole number", pgtype.Numeric{Int: big.NewInt(12), Exp: 0, Valid: true}, 1200},
+		{"positive exponent", pgtype.Numeric{Int: big.NewInt(5), Exp: 3, Valid: true}, 500000},
+		{"half rounds up", pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true}, 1235},
+		{"below half rounds down", pgtype.Numeric{Int: big.NewInt(12344), Exp: -3, Valid: true}, 1234},
+		{"negative half rounds away from zero", pgtype.Numeric{Int: big.NewInt(-12345), Exp: -3, Valid: true}, -1235},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		if err := got.ScanNumeric(tt.in); err != nil {
+			t.Errorf("%s: ScanNumeric returned error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: ScanNumeric = %d, want %d", tt.name, got, tt.want)
+		}
+
+		value, err := got.NumericValue()
+		if err != nil {
+			t.Errorf("%s: NumericValue returned error: %v", tt.name, err)
+			continue
+		}
+		var roundTrip Money
+		if err := roundTrip.ScanNumeric(value); err != nil || roundTrip != got {
+			t.Errorf("%s: round trip of %d = %d, %v", tt.name, got, roundTrip, err)
+		}
+	}
+
+	invalid := []pgtype.Numeric{
+		{},
+		{Valid: true, NaN: true},
+		{Valid: true, InfinityModifier: pgtype.Infinity},
+		{Int: new(big.Int).Lsh(big.NewInt(1), 70), Exp: 0, Valid: true},
+	}
+	for _, in := range invalid {
+		var m Money
+		if err := m.ScanNumeric(in); err == nil {
+			t.Errorf("ScanNumeric(%+v) = %d, want error", in, m)
+		}
+	}
+}
+
+func TestMulBasisPoints(t *testing.T) {
+	tests := []struct {
+		amount Money
+		bps    int64
+		want   Money
+	}{
+		{10000, 100, 100},
+		{10000, 10000, 10000},
+		{10000, 0, 0},
+		{0, 500, 0},
+		{150, 100, 2},   // 1.5 rounds up
+		{149, 100, 1},   // 1.49 rounds down
+		{250, 100, 3},   // 2.5 rounds away from zero
+		{-150, 100, -2}, // -1.5 rounds away from zero
+		{-149, 100, -1},
+		{1, 5000, 1}, // 0.5 rounds up
+		{1, 4999, 0},
+		{123456789, 25, 308642}, // 308641.9725
+		{9000000000000000, 10000, 9000000000000000},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.MulBasisPoints(tt.bps); got != tt.want {
+			t.Errorf("Money(%d).MulBasisPoints(%d) = %d, want %d", tt.amount, tt.bps, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// PaymentImportMapping names the settlement file column that holds each
+// payment notification field. Status is optional; rows without it are
+// treated as COMPLETE. DateLayout is the Go time layout of the date column.
+type PaymentImportMapping struct {
+	CustomerID string `json:"customer_id"`
+	Reference  string `json:"transaction_reference"`
+	Amount     string `json:"transaction_amount"`
+	Date       string `json:"transaction_date"`
+	Status     string `json:"payment_status"`
+	DateLayout string `json:"date_layout"`
+}
+
+// ParsePaymentImportMapping parses a comma separated list of field=column
+// pairs, such as "customer_id=Customer Ref,transaction_amount=Amount". Fields
+// that are not listed are left empty.
+func ParsePaymentImportMapping(spec string) (*PaymentImportMapping, error) {
+	mapping := &PaymentImportMapping{}
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", strings.TrimSpace(pair))
+		}
+
+		switch field {
+		case "customer_id":
+			mapping.CustomerID = column
+		case "transaction_reference":
+			mapping.Reference = column
+		case "transaction_amount":
+			mapping.Amount = column
+		case "transaction_date":
+			mapping.Date = column
+		case "payment_status":
+			mapping.Status = column
+		default:
+			return nil, fmt.Errorf("unknown column mapping field %q", field)
+		}
+	}
+
+	return mapping, nil
+}
+
+// Merge returns a copy of m with every field set in overrides replaced
+func (m PaymentImportMapping) Merge(overrides *PaymentImportMapping) *PaymentImportMapping {
+	if overrides == nil {
+		return &m
+	}
+
+	if overrides.CustomerID != "" {
+		m.CustomerID = overrides.CustomerID
+	}
+	if overrides.Reference != "" {
+		m.Reference = overrides.Reference
+	}
+	if overrides.Amount != "" {
+		m.Amount = overrides.Amount
+	}
+	if overrides.Date != "" {
+		m.Date = overrides.Date
+	}
+	if overrides.Status != "" {
+		m.Status = overrides.Status
+	}
+	if overrides.DateLayout != "" {
+		m.DateLayout = overrides.DateLayout
+	}
+
+	return &m
+}
+
+type PaymentImportRowStatus string
+
+const (
+	// PaymentImportQueued rows are COMPLETE payments the background workers will credit
+	PaymentImportQueued PaymentImportRowStatus = "QUEUED"
+	// PaymentImportPending rows are PENDING payments, recorded but not credited
+	PaymentImportPending PaymentImportRowStatus = "PENDING"
+	// PaymentImportDeclined rows are FAILED or CANCELLED payments, which are
+	// never credited
+	PaymentImportDeclined PaymentImportRowStatus = "DECLINED"
+
+	PaymentImportDuplicate       PaymentImportRowStatus = "DUPLICATE"
+	PaymentImportUnknownCustomer PaymentImportRowStatus = "UNKNOWN_CUSTOMER"
+	PaymentImportInvalidAmount   PaymentImportRowStatus = "INVALID_AMOUNT"
+	PaymentImportInvalid         PaymentImportRowStatus = "INVALID"
+	PaymentImportFailed          PaymentImportRowStatus = "FAILED"
+)
+
+// PaymentImportRow is the outcome of one settlement file row. Line is the
+// line number in the file, counting the header as line 1.
+type PaymentImportRow struct {
+	Line          int                    `json:"line"`
+	Reference     string                 `json:"transaction_reference"`
+	CustomerID    string                 `json:"customer_id"`
+	Amount        string                 `json:"transaction_amount"`
+	Status        PaymentImportRowStatus `json:"status"`
+	TransactionID *int64                 `json:"-"`
+	Error         string                 `json:"error,omitempty"`
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted transaction_id when known
+func (r *PaymentImportRow) MarshalJSON() ([]byte, error) {
+	type Alias PaymentImportRow
+
+	var transactionID string
+	if r.TransactionID != nil {
+		transactionID = utils.FormatTransactionID(*r.TransactionID)
+	}
+
+	return json.Marshal(struct {
+		TransactionID string `json:"transaction_id,omitempty"`
+		Alias
+	}{
+		TransactionID: transactionID,
+		Alias:         (Alias)(*r),
+	})
+}
+
+// PaymentImportReport summarises a settlement file import
+type PaymentImportReport struct {
+	Rows    int                            `json:"rows"`
+	Summary map[PaymentImportRowStatus]int `json:"summary"`
+	Results []*PaymentImportRow            `json:"results"`
+}
+
+// Add records the outcome of a row
+func (r *PaymentImportReport) Add(row *PaymentImportRow) {
+	r.Rows++
+	r.Summary[row.Status]++
+	r.Results = append(r.Results, row)
+}
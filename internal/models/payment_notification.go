@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationStatusReceived  NotificationStatus = "RECEIVED"
+	NotificationStatusProcessed NotificationStatus = "PROCESSED"
+	NotificationStatusFailed    NotificationStatus = "FAILED"
+	// NotificationStatusSuspended is only reported to providers: the payment's
+	// customer is unknown, so it is held as a suspense payment instead
+	NotificationStatusSuspended NotificationStatus = "SUSPENDED"
+)
+
+// PaymentNotification is a payment notification as received from a provider.
+// Its reference is unique, which makes notification handling idempotent.
+type PaymentNotification struct {
+	ID              int64              `json:"-"`
+	Provider        string             `json:"provider"`
+	Reference       string             `json:"transaction_reference"`
+	CustomerRef     string             `json:"customer_ref"`
+	AccountID       *int64             `json:"-"`
+	TransactionID   *int64             `json:"-"`
+	PaymentStatus   PaymentStatus      `json:"payment_status"`
+	Amount          Money              `json:"amount"`
+	TransactionDate time.Time          `json:"transaction_date"`
+	Status          NotificationStatus `json:"status"`
+	Error           *string            `json:"error,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// PaymentNotificationResult is returned to the provider for every notification.
+// Replayed is true when the reference had already been received, in which case
+// the original outcome is returned and nothing is credited again.
+type PaymentNotificationResult struct {
+	Reference     string             `json:"transaction_reference"`
+	Status        NotificationStatus `json:"status"`
+	TransactionID *int64             `json:"-"`
+	SuspenseID    *int64             `json:"suspense_id,omitempty"`
+	Replayed      bool               `json:"replayed"`
+}
+
+// NewPaymentNotificationResult builds the result for a stored notification
+func NewPaymentNotificationResult(n *PaymentNotification, replayed bool) *PaymentNotificationResult {
+	return &PaymentNotificationResult{
+		Reference:     n.Reference,
+		Status:        n.Status,
+		TransactionID: n.TransactionID,
+		Replayed:      replayed,
+	}
+}
+
+// NewSuspendedPaymentResult builds the result for a payment held in suspense
+func NewSuspendedPaymentResult(p *SuspensePayment, replayed bool) *PaymentNotificationResult {
+	return &PaymentNotificationResult{
+		Reference:  p.Reference,
+		Status:     NotificationStatusSuspended,
+		SuspenseID: &p.ID,
+		Replayed:   replayed,
+	}
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted transaction_id when known
+func (r *PaymentNotificationResult) MarshalJSON() ([]byte, error) {
+	type Alias PaymentNotificationResult
+
+	var transactionID string
+	if r.TransactionID != nil {
+		transactionID = utils.FormatTransactionID(*r.TransactionID)
+	}
+
+	return json.Marshal(struct {
+		TransactionID string `json:"transaction_id,omitempty"`
+		Alias
+	}{
+		TransactionID: transactionID,
+		Alias:         (Alias)(*r),
+	})
+}
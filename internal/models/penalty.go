@@ -0,0 +1,144 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type PenaltyType string
+
+const (
+	// PenaltyTypeFlat charges a fixed amount per day overdue
+	PenaltyTypeFlat PenaltyType = "FLAT"
+	// PenaltyTypePercentage charges a share of the overdue principal per day overdue
+	PenaltyTypePercentage PenaltyType = "PERCENTAGE"
+)
+
+// PenaltyRule describes the late fee charged on overdue installments. A rule
+// with a ProductID applies to deployments of that product only and takes
+// precedence over rules without one.
+type PenaltyRule struct {
+	ID             int64       `json:"id"`
+	Name           string      `json:"name"`
+	Type           PenaltyType `json:"type"`
+	Amount         Money       `json:"amount"`   // Used by FLAT rules
+	RateBps        int64       `json:"rate_bps"` // Used by PERCENTAGE rules, in hundredths of a percent
+	GraceDays      int         `json:"grace_days"`
+	InstallmentCap *Money      `json:"installment_cap,omitempty"`
+	DeploymentCap  *Money      `json:"deployment_cap,omitempty"`
+	ProductID      *int64      `json:"-"`
+	Active         bool        `json:"active"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted product_id
+func (r *PenaltyRule) MarshalJSON() ([]byte, error) {
+	type Alias PenaltyRule
+
+	var productID string
+	if r.ProductID != nil {
+		productID = utils.FormatProductID(*r.ProductID)
+	}
+
+	return json.Marshal(struct {
+		ProductID string `json:"product_id,omitempty"`
+		Alias
+	}{
+		ProductID: productID,
+		Alias:     (Alias)(*r),
+	})
+}
+
+// DailyCharge returns the uncapped charge for one day on an installment
+func (r *PenaltyRule) DailyCharge(installment *Installment) Money {
+	if r.Type == PenaltyTypePercentage {
+		return installment.PrincipalOutstanding().MulBasisPoints(r.RateBps)
+	}
+	return r.Amount
+}
+
+// Capped limits a day's charge to what the caps leave after installmentCharged
+// on the installment and deploymentCharged on its schedule. It never returns a
+// negative amount.
+func (r *PenaltyRule) Capped(amount, installmentCharged, deploymentCharged Money) Money {
+	if r.InstallmentCap != nil {
+		amount = min(amount, *r.InstallmentCap-installmentCharged)
+	}
+	if r.DeploymentCap != nil {
+		amount = min(amount, *r.DeploymentCap-deploymentCharged)
+	}
+	return max(amount, 0)
+}
+
+// IsChargeable reports whether the installment is past its due date plus the
+// grace days on date
+func (r *PenaltyRule) IsChargeable(installment *Installment, date time.Time) bool {
+	return installment.DueDate.AddDate(0, 0, r.GraceDays).Before(date)
+}
+
+type CreatePenaltyRuleRequest struct {
+	Name           string      `json:"name" validate:"required"`
+	Type           PenaltyType `json:"type" validate:"required,oneof=FLAT PERCENTAGE"`
+	Amount         Money       `json:"amount" validate:"required_if=Type FLAT,gte=0"`
+	RateBps        int64       `json:"rate_bps" validate:"required_if=Type PERCENTAGE,gte=0,lte=10000"`
+	GraceDays      int         `json:"grace_days" validate:"gte=0"`
+	InstallmentCap *Money      `json:"installment_cap,omitempty" validate:"omitempty,gt=0"`
+	DeploymentCap  *Money      `json:"deployment_cap,omitempty" validate:"omitempty,gt=0"`
+	ProductID      string      `json:"product_id,omitempty"`
+}
+
+type UpdatePenaltyRuleRequest struct {
+	Name           *string `json:"name,omitempty" validate:"omitempty"`
+	Amount         *Money  `json:"amount,omitempty" validate:"omitempty,gte=0"`
+	RateBps        *int64  `json:"rate_bps,omitempty" validate:"omitempty,gte=0,lte=10000"`
+	GraceDays      *int    `json:"grace_days,omitempty" validate:"omitempty,gte=0"`
+	InstallmentCap *Money  `json:"installment_cap,omitempty" validate:"omitempty,gt=0"`
+	DeploymentCap  *Money  `json:"deployment_cap,omitempty" validate:"omitempty,gt=0"`
+	Active         *bool   `json:"active,omitempty"`
+}
+
+// PenaltyCharge is a late fee posted on an installment for one day
+type PenaltyCharge struct {
+	ID            int64      `json:"id"`
+	RuleID        *int64     `json:"rule_id,omitempty"`
+	ScheduleID    int64      `json:"schedule_id"`
+	InstallmentID int64      `json:"installment_id"`
+	TransactionID int64      `json:"-"`
+	ChargeDate    time.Time  `json:"-"`
+	Amount        Money      `json:"amount"`
+	ReversedAt    *time.Time `json:"reversed_at,omitempty"` // Set once the FEE transaction is reversed
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// OverdueInstallment is an unpaid installment past its due date together with
+// the deployment details needed to charge it
+type OverdueInstallment struct {
+	Installment *Installment
+	CustomerID  int64
+	AccountID   int64
+	ProductID   *int64
+}
+
+// PenaltyEvaluation summarises one run of the penalty evaluator
+type PenaltyEvaluation struct {
+	Date      time.Time `json:"-"`
+	Evaluated int       `json:"evaluated"`
+	Charged   int       `json:"charged"`
+	Amount    Money     `json:"amount"`
+}
+
+// MarshalJSON customizes JSON marshaling to format the evaluation date
+func (e *PenaltyEvaluation) MarshalJSON() ([]byte, error) {
+	type Alias PenaltyEvaluation
+
+	return json.Marshal(struct {
+		Date string `json:"date"`
+		Alias
+	}{
+		Date:  e.Date.Format(DateLayout),
+		Alias: (Alias)(*e),
+	})
+}
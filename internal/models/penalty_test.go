@@ -0,0 +1,81 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPenaltyRuleDailyCharge(t *testing.T) {
+	installment := &Installment{AmountDue: 10000, AmountPaid: 2500, PenaltyDue: 900}
+
+	tests := []struct {
+		name string
+		rule PenaltyRule
+		want Money
+	}{
+		{"flat", PenaltyRule{Type: PenaltyTypeFlat, Amount: 500, RateBps: 100}, 500},
+		{"percentage of the overdue principal", PenaltyRule{Type: PenaltyTypePercentage, Amount: 500, RateBps: 100}, 75},
+		{"percentage rounds half away from zero", PenaltyRule{Type: PenaltyTypePercentage, RateBps: 2}, 2}, // 1.5
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.DailyCharge(installment); got != tt.want {
+			t.Errorf("%s: DailyCharge = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPenaltyRuleCapped(t *testing.T) {
+	capAt := func(m Money) *Money { return &m }
+
+	tests := []struct {
+		name               string
+		installmentCap     *Money
+		deploymentCap      *Money
+		amount             Money
+		installmentCharged Money
+		deploymentCharged  Money
+		want               Money
+	}{
+		{"no caps", nil, nil, 500, 10000, 10000, 500},
+		{"under the installment cap", capAt(2000), nil, 500, 1000, 0, 500},
+		{"installment cap leaves part of the charge", capAt(2000), nil, 500, 1800, 0, 200},
+		{"installment cap reached", capAt(2000), nil, 500, 2000, 0, 0},
+		{"installment cap exceeded", capAt(2000), nil, 500, 2100, 0, 0},
+		{"deployment cap leaves part of the charge", nil, capAt(5000), 500, 0, 4900, 100},
+		{"deployment cap reached", nil, capAt(5000), 500, 0, 5000, 0},
+		{"tighter installment cap wins", capAt(2000), capAt(5000), 500, 1700, 4000, 300},
+		{"tighter deployment cap wins", capAt(2000), capAt(5000), 500, 1000, 4600, 400},
+	}
+
+	for _, tt := range tests {
+		rule := PenaltyRule{InstallmentCap: tt.installmentCap, DeploymentCap: tt.deploymentCap}
+		if got := rule.Capped(tt.amount, tt.installmentCharged, tt.deploymentCharged); got != tt.want {
+			t.Errorf("%s: Capped = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPenaltyRuleIsChargeable(t *testing.T) {
+	due := time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)
+	installment := &Installment{DueDate: due}
+
+	tests := []struct {
+		name      string
+		graceDays int
+		date      time.Time
+		want      bool
+	}{
+		{"on the due date", 0, due, false},
+		{"the day after the due date", 0, due.AddDate(0, 0, 1), true},
+		{"last day of grace", 3, due.AddDate(0, 0, 3), false},
+		{"first day after grace", 3, due.AddDate(0, 0, 4), true},
+	}
+
+	for _, tt := range tests {
+		rule := PenaltyRule{GraceDays: tt.graceDays}
+		if got := rule.IsChargeable(installment, tt.date); got != tt.want {
+			t.Errorf("%s: IsChargeable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type AssetType string
+
+const (
+	AssetTypeMotorcycle AssetType = "MOTORCYCLE"
+	AssetTypeTricycle   AssetType = "TRICYCLE"
+	AssetTypeEVBike     AssetType = "EV_BIKE"
+)
+
+// Product is a financed asset offering with its price and repayment terms
+type Product struct {
+	ID        int64              `json:"-"`
+	Name      string             `json:"name"`
+	AssetType AssetType          `json:"asset_type"`
+	Price     Money              `json:"price"`
+	Tenor     int                `json:"tenor"`
+	Frequency RepaymentFrequency `json:"frequency"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	DeletedAt *time.Time         `json:"deleted_at,omitempty"`
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted product_id
+func (p *Product) MarshalJSON() ([]byte, error) {
+	type Alias Product
+
+	return json.Marshal(struct {
+		ID string `json:"id"`
+		Alias
+	}{
+		ID:    utils.FormatProductID(p.ID),
+		Alias: (Alias)(*p),
+	})
+}
+
+type CreateProductRequest struct {
+	Name      string             `json:"name" validate:"required"`
+	AssetType AssetType          `json:"asset_type" validate:"required,oneof=MOTORCYCLE TRICYCLE EV_BIKE"`
+	Price     Money              `json:"price" validate:"required,gt=0"`
+	Tenor     int                `json:"tenor" validate:"required,min=1,max=1000"`
+	Frequency RepaymentFrequency `json:"frequency" validate:"required,oneof=DAILY WEEKLY MONTHLY"`
+}
+
+type UpdateProductRequest struct {
+	Name      *string             `json:"name,omitempty" validate:"omitempty"`
+	AssetType *AssetType          `json:"asset_type,omitempty" validate:"omitempty,oneof=MOTORCYCLE TRICYCLE EV_BIKE"`
+	Price     *Money              `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Tenor     *int                `json:"tenor,omitempty" validate:"omitempty,min=1,max=1000"`
+	Frequency *RepaymentFrequency `json:"frequency,omitempty" validate:"omitempty,oneof=DAILY WEEKLY MONTHLY"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// BalanceCheck compares an account's stored balance with the balance expected
+// from its transaction history
+type BalanceCheck struct {
+	AccountID       int64  `json:"-"`
+	CustomerID      int64  `json:"-"`
+	Balance         Money  `json:"balance"`
+	ExpectedBalance Money  `json:"expected_balance"`
+	AdjustmentID    *int64 `json:"-"`
+}
+
+// Difference is how far the expected balance is above the stored balance
+func (c *BalanceCheck) Difference() Money {
+	return c.ExpectedBalance - c.Balance
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted IDs and the difference
+func (c *BalanceCheck) MarshalJSON() ([]byte, error) {
+	type Alias BalanceCheck
+
+	var adjustmentID string
+	if c.AdjustmentID != nil {
+		adjustmentID = utils.FormatTransactionID(*c.AdjustmentID)
+	}
+
+	return json.Marshal(struct {
+		AccountID    string `json:"account_id"`
+		CustomerID   string `json:"customer_id"`
+		Difference   Money  `json:"difference"`
+		AdjustmentID string `json:"adjustment_id,omitempty"`
+		Alias
+	}{
+		AccountID:    utils.FormatAccountID(c.AccountID),
+		CustomerID:   utils.FormatCustomerID(c.CustomerID),
+		Difference:   c.Difference(),
+		AdjustmentID: adjustmentID,
+		Alias:        (Alias)(*c),
+	})
+}
+
+type ReconciliationReport struct {
+	DryRun          bool      `json:"dry_run"`
+	RunAt           time.Time `json:"run_at"`
+	AccountsChecked int       `json:"accounts_checked"`
+	Adjusted        int       `json:"adjusted"`
+	// Resolved counts mismatches that no longer differed once the account was
+	// locked, because a payment was credited while the run was in progress
+	Resolved   int             `json:"resolved"`
+	Mismatches []*BalanceCheck `json:"mismatches"`
+}
+
+// Unresolved is how many mismatches were neither adjusted nor resolved
+func (r *ReconciliationReport) Unresolved() int {
+	return len(r.Mismatches) - r.Adjusted - r.Resolved
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// ReportGroupByMonth splits a report's date range into calendar months
+const ReportGroupByMonth = "month"
+
+// PortfolioReportRequest holds the parsed query of the portfolio report
+type PortfolioReportRequest struct {
+	From    time.Time
+	To      time.Time
+	AsOf    time.Time
+	GroupBy string
+}
+
+// PortfolioAtRisk is the outstanding principal of deployments with an
+// installment more than a number of days past due, and its share of the
+// total outstanding principal in percent
+type PortfolioAtRisk struct {
+	Amount Money   `json:"amount"`
+	Ratio  float64 `json:"ratio"`
+}
+
+// PortfolioSnapshot describes the book of active deployments on a date
+type PortfolioSnapshot struct {
+	AsOf              time.Time       `json:"-"`
+	ActiveDeployments int             `json:"active_deployments"`
+	Outstanding       Money           `json:"outstanding"`
+	PAR7              PortfolioAtRisk `json:"par7"`
+	PAR30             PortfolioAtRisk `json:"par30"`
+	PAR90             PortfolioAtRisk `json:"par90"`
+}
+
+// MarshalJSON customizes JSON marshaling to format the as-of date
+func (s *PortfolioSnapshot) MarshalJSON() ([]byte, error) {
+	type Alias PortfolioSnapshot
+
+	return json.Marshal(struct {
+		AsOf string `json:"as_of"`
+		Alias
+	}{
+		AsOf:  s.AsOf.Format(DateLayout),
+		Alias: (Alias)(*s),
+	})
+}
+
+// PortfolioActivity sums disbursements and collections over a date range
+type PortfolioActivity struct {
+	From                time.Time `json:"-"`
+	To                  time.Time `json:"-"`
+	DeploymentsCount    int       `json:"deployments_count"`
+	DisbursedAmount     Money     `json:"disbursed_amount"`
+	ExpectedCollections Money     `json:"expected_collections"`
+	Collections         Money     `json:"collections"`
+	CollectionRate      float64   `json:"collection_rate"`
+}
+
+// MarshalJSON customizes JSON marshaling to format the date range
+func (a *PortfolioActivity) MarshalJSON() ([]byte, error) {
+	type Alias PortfolioActivity
+
+	return json.Marshal(struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Alias
+	}{
+		From:  a.From.Format(DateLayout),
+		To:    a.To.Format(DateLayout),
+		Alias: (Alias)(*a),
+	})
+}
+
+// PortfolioPeriod is one group of a grouped portfolio report
+type PortfolioPeriod struct {
+	Period   string             `json:"period"`
+	Activity *PortfolioActivity `json:"activity"`
+	Snapshot *PortfolioSnapshot `json:"snapshot"`
+}
+
+type PortfolioReport struct {
+	Snapshot *PortfolioSnapshot `json:"snapshot"`
+	Activity *PortfolioActivity `json:"activity"`
+	Periods  []*PortfolioPeriod `json:"periods,omitempty"`
+}
+
+// Percentage returns part as a percentage of whole, rounded to two decimal places
+func Percentage(part, whole Money) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
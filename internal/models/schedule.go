@@ -0,0 +1,147 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// DateLayout is the format used for calendar dates such as installment due dates
+const DateLayout = "2006-01-02"
+
+type RepaymentFrequency string
+
+const (
+	RepaymentFrequencyDaily   RepaymentFrequency = "DAILY"
+	RepaymentFrequencyWeekly  RepaymentFrequency = "WEEKLY"
+	RepaymentFrequencyMonthly RepaymentFrequency = "MONTHLY"
+)
+
+// IsValid reports whether f is a supported repayment frequency
+func (f RepaymentFrequency) IsValid() bool {
+	switch f {
+	case RepaymentFrequencyDaily, RepaymentFrequencyWeekly, RepaymentFrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive  ScheduleStatus = "ACTIVE"
+	ScheduleStatusSettled ScheduleStatus = "SETTLED"
+	// ScheduleStatusCancelled schedules belong to a reversed deployment and are
+	// no longer due
+	ScheduleStatusCancelled ScheduleStatus = "CANCELLED"
+)
+
+type InstallmentStatus string
+
+const (
+	InstallmentStatusPending InstallmentStatus = "PENDING"
+	InstallmentStatusPartial InstallmentStatus = "PARTIAL"
+	InstallmentStatusPaid    InstallmentStatus = "PAID"
+)
+
+// RepaymentSchedule splits a deployment into installments
+type RepaymentSchedule struct {
+	ID            int64              `json:"-"`
+	DeploymentID  int64              `json:"-"`
+	TransactionID int64              `json:"-"`
+	CustomerID    int64              `json:"-"`
+	AccountID     int64              `json:"-"`
+	ProductID     *int64             `json:"-"`
+	Principal     Money              `json:"principal"`
+	Tenor         int                `json:"tenor"`
+	Frequency     RepaymentFrequency `json:"frequency"`
+	StartDate     time.Time          `json:"-"`
+	Status        ScheduleStatus     `json:"status"`
+	Installments  []*Installment     `json:"installments"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include formatted IDs and the start date
+func (s *RepaymentSchedule) MarshalJSON() ([]byte, error) {
+	type Alias RepaymentSchedule
+
+	var productID string
+	if s.ProductID != nil {
+		productID = utils.FormatProductID(*s.ProductID)
+	}
+
+	return json.Marshal(struct {
+		ID            int64  `json:"id"`
+		DeploymentID  string `json:"deployment_id"`
+		TransactionID string `json:"transaction_id"`
+		CustomerID    string `json:"customer_id"`
+		AccountID     string `json:"account_id"`
+		ProductID     string `json:"product_id,omitempty"`
+		StartDate     string `json:"start_date"`
+		Alias
+	}{
+		ID:            s.ID,
+		DeploymentID:  utils.FormatDeploymentID(s.DeploymentID),
+		TransactionID: utils.FormatTransactionID(s.TransactionID),
+		CustomerID:    utils.FormatCustomerID(s.CustomerID),
+		AccountID:     utils.FormatAccountID(s.AccountID),
+		ProductID:     productID,
+		StartDate:     s.StartDate.Format(DateLayout),
+		Alias:         (Alias)(*s),
+	})
+}
+
+// Installment is one repayment period. AmountDue and AmountPaid are the
+// principal portion; penalties and fees charged on the installment are tracked
+// separately so allocations can settle them first.
+type Installment struct {
+	ID          int64             `json:"id"`
+	ScheduleID  int64             `json:"-"`
+	Sequence    int               `json:"sequence"`
+	DueDate     time.Time         `json:"-"`
+	AmountDue   Money             `json:"amount_due"`
+	AmountPaid  Money             `json:"amount_paid"`
+	FeeDue      Money             `json:"fee_due"`
+	FeePaid     Money             `json:"fee_paid"`
+	PenaltyDue  Money             `json:"penalty_due"`
+	PenaltyPaid Money             `json:"penalty_paid"`
+	Status      InstallmentStatus `json:"status"`
+	PaidAt      *time.Time        `json:"paid_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// PrincipalOutstanding returns the principal still owed on the installment
+func (i *Installment) PrincipalOutstanding() Money {
+	return i.AmountDue - i.AmountPaid
+}
+
+// FeeOutstanding returns the fees still owed on the installment
+func (i *Installment) FeeOutstanding() Money {
+	return i.FeeDue - i.FeePaid
+}
+
+// PenaltyOutstanding returns the penalties still owed on the installment
+func (i *Installment) PenaltyOutstanding() Money {
+	return i.PenaltyDue - i.PenaltyPaid
+}
+
+// Outstanding returns the total still owed on the installment
+func (i *Installment) Outstanding() Money {
+	return i.PrincipalOutstanding() + i.FeeOutstanding() + i.PenaltyOutstanding()
+}
+
+// MarshalJSON customizes JSON marshaling to format the due date as a calendar date
+func (i *Installment) MarshalJSON() ([]byte, error) {
+	type Alias Installment
+
+	return json.Marshal(struct {
+		DueDate string `json:"due_date"`
+		Alias
+	}{
+		DueDate: i.DueDate.Format(DateLayout),
+		Alias:   (Alias)(*i),
+	})
+}
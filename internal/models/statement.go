@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type StatementFormat string
+
+const (
+	StatementFormatJSON StatementFormat = "json"
+	StatementFormatCSV  StatementFormat = "csv"
+	StatementFormatPDF  StatementFormat = "pdf"
+)
+
+// IsValid reports whether f is a supported statement format
+func (f StatementFormat) IsValid() bool {
+	switch f {
+	case StatementFormatJSON, StatementFormatCSV, StatementFormatPDF:
+		return true
+	}
+	return false
+}
+
+// Statement lists the posted transactions of a customer's account between two
+// dates, with the balance before, after and in between them
+type Statement struct {
+	Customer       *Customer        `json:"customer"`
+	Account        *Account         `json:"account"`
+	From           time.Time        `json:"-"`
+	To             time.Time        `json:"-"`
+	OpeningBalance Money            `json:"opening_balance"`
+	TotalCredits   Money            `json:"total_credits"`
+	TotalDebits    Money            `json:"total_debits"`
+	ClosingBalance Money            `json:"closing_balance"`
+	Lines          []*StatementLine `json:"lines"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to format the statement period
+func (s *Statement) MarshalJSON() ([]byte, error) {
+	type Alias Statement
+
+	return json.Marshal(struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Alias
+	}{
+		From:  s.From.Format(DateLayout),
+		To:    s.To.Format(DateLayout),
+		Alias: (Alias)(*s),
+	})
+}
+
+// StatementLine is one transaction on a statement with the balance after it
+type StatementLine struct {
+	TransactionID   int64           `json:"-"`
+	TransactionDate time.Time       `json:"transaction_date"`
+	Reference       string          `json:"reference"`
+	Type            TransactionType `json:"type"`
+	Description     string          `json:"description"`
+	Direction       Direction       `json:"direction"`
+	Amount          Money           `json:"amount"`
+	Balance         Money           `json:"balance"`
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted transaction_id
+func (l *StatementLine) MarshalJSON() ([]byte, error) {
+	type Alias StatementLine
+
+	return json.Marshal(struct {
+		TransactionID string `json:"transaction_id"`
+		Alias
+	}{
+		TransactionID: utils.FormatTransactionID(l.TransactionID),
+		Alias:         (Alias)(*l),
+	})
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type SuspenseStatus string
+
+const (
+	SuspenseStatusUnmatched SuspenseStatus = "UNMATCHED"
+	SuspenseStatusMatched   SuspenseStatus = "MATCHED"
+	SuspenseStatusRefunded  SuspenseStatus = "REFUNDED"
+)
+
+// IsValid reports whether s is a known suspense status
+func (s SuspenseStatus) IsValid() bool {
+	switch s {
+	case SuspenseStatusUnmatched, SuspenseStatusMatched, SuspenseStatusRefunded:
+		return true
+	}
+	return false
+}
+
+type SuspenseAction string
+
+const (
+	SuspenseActionReceived SuspenseAction = "RECEIVED"
+	SuspenseActionMatched  SuspenseAction = "MATCHED"
+	SuspenseActionRefunded SuspenseAction = "REFUNDED"
+)
+
+// SuspensePayment is a completed payment whose customer could not be
+// identified. CustomerRef is the customer ID as the provider sent it.
+type SuspensePayment struct {
+	ID              int64            `json:"id"`
+	Provider        string           `json:"provider"`
+	Reference       string           `json:"transaction_reference"`
+	CustomerRef     string           `json:"customer_ref"`
+	Amount          Money            `json:"amount"`
+	TransactionDate time.Time        `json:"transaction_date"`
+	Reason          string           `json:"reason"`
+	Status          SuspenseStatus   `json:"status"`
+	CustomerID      *int64           `json:"-"`
+	NotificationID  *int64           `json:"-"`
+	ResolvedBy      *string          `json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time       `json:"resolved_at,omitempty"`
+	Note            *string          `json:"note,omitempty"`
+	Events          []*SuspenseEvent `json:"events,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted customer_id once matched
+func (p *SuspensePayment) MarshalJSON() ([]byte, error) {
+	type Alias SuspensePayment
+
+	var customerID string
+	if p.CustomerID != nil {
+		customerID = utils.FormatCustomerID(*p.CustomerID)
+	}
+
+	return json.Marshal(struct {
+		CustomerID string `json:"customer_id,omitempty"`
+		Alias
+	}{
+		CustomerID: customerID,
+		Alias:      (Alias)(*p),
+	})
+}
+
+// SuspenseEvent records an action taken on a suspense payment and who took it
+type SuspenseEvent struct {
+	ID         int64          `json:"-"`
+	SuspenseID int64          `json:"-"`
+	Action     SuspenseAction `json:"action"`
+	Actor      string         `json:"actor"`
+	CustomerID *int64         `json:"-"`
+	Note       *string        `json:"note,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// MarshalJSON customizes JSON marshaling to include the formatted customer_id when set
+func (e *SuspenseEvent) MarshalJSON() ([]byte, error) {
+	type Alias SuspenseEvent
+
+	var customerID string
+	if e.CustomerID != nil {
+		customerID = utils.FormatCustomerID(*e.CustomerID)
+	}
+
+	return json.Marshal(struct {
+		CustomerID string `json:"customer_id,omitempty"`
+		Alias
+	}{
+		CustomerID: customerID,
+		Alias:      (Alias)(*e),
+	})
+}
+
+// MatchSuspenseRequest matches a suspense payment to a customer. Actor is the
+// authenticated admin, never taken from the request body.
+type MatchSuspenseRequest struct {
+	CustomerID string `json:"customer_id" validate:"required"`
+	Actor      string `json:"-" validate:"required"`
+	Note       string `json:"note,omitempty"`
+}
+
+// RefundSuspenseRequest marks a suspense payment refunded. Actor is the
+// authenticated admin, never taken from the request body.
+type RefundSuspenseRequest struct {
+	Actor string `json:"-" validate:"required"`
+	Note  string `json:"note,omitempty"`
+}
@@ -16,53 +16,135 @@ const (
 	PaymentStatusCancelled PaymentStatus = "CANCELLED"
 )
 
+// paymentStatusTransitions lists the statuses each status may move to.
+// COMPLETE, FAILED and CANCELLED are terminal.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending:   {PaymentStatusComplete, PaymentStatusFailed, PaymentStatusCancelled},
+	PaymentStatusComplete:  {},
+	PaymentStatusFailed:    {},
+	PaymentStatusCancelled: {},
+}
+
+// IsValid reports whether s is a known payment status
+func (s PaymentStatus) IsValid() bool {
+	_, ok := paymentStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a transaction in status s may move to next
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type TransactionType string
+
+const (
+	TransactionTypePayment    TransactionType = "PAYMENT"
+	TransactionTypeDeployment TransactionType = "DEPLOYMENT"
+	TransactionTypeFee        TransactionType = "FEE"
+	TransactionTypeReversal   TransactionType = "REVERSAL"
+	TransactionTypeAdjustment TransactionType = "ADJUSTMENT"
+)
+
+// IsValid reports whether t is a known transaction type
+func (t TransactionType) IsValid() bool {
+	switch t {
+	case TransactionTypePayment, TransactionTypeDeployment, TransactionTypeFee, TransactionTypeReversal, TransactionTypeAdjustment:
+		return true
+	}
+	return false
+}
+
 type Transaction struct {
-	ID              int64         `json:"-"`
-	CustomerID      int64         `json:"-"`
-	AccountID       int64         `json:"-"`
-	Reference       string        `json:"reference"`
-	Amount          float64       `json:"amount"`
-	Status          PaymentStatus `json:"status"`
-	Description     *string       `json:"description,omitempty"`
-	TransactionDate time.Time     `json:"transaction_date"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
+	ID              int64           `json:"-"`
+	CustomerID      int64           `json:"-"`
+	AccountID       int64           `json:"-"`
+	Reference       string          `json:"reference"`
+	Type            TransactionType `json:"type"`
+	Direction       Direction       `json:"direction"`
+	Amount          Money           `json:"amount"`
+	Status          PaymentStatus   `json:"status"`
+	Description     *string         `json:"description,omitempty"`
+	ReversalOf      *int64          `json:"-"`
+	TransactionDate time.Time       `json:"transaction_date"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// SignedAmount returns the amount as it affects the account balance:
+// positive for credits and negative for debits
+func (t *Transaction) SignedAmount() Money {
+	if t.Direction == DirectionDebit {
+		return -t.Amount
+	}
+	return t.Amount
 }
 
 // MarshalJSON customizes JSON marshaling to include formatted transaction_id, customer_id, and account_id
 func (t *Transaction) MarshalJSON() ([]byte, error) {
 	type Alias Transaction
 
+	var reversalOf string
+	if t.ReversalOf != nil {
+		reversalOf = utils.FormatTransactionID(*t.ReversalOf)
+	}
+
 	return json.Marshal(struct {
-		ID         string `json:"id"`
-		CustomerID string `json:"customer_id"`
-		AccountID  string `json:"account_id"`
+		ID           string `json:"id"`
+		CustomerID   string `json:"customer_id"`
+		AccountID    string `json:"account_id"`
+		SignedAmount Money  `json:"signed_amount"`
+		ReversalOf   string `json:"reversal_of,omitempty"`
 		Alias
 	}{
-		ID:         utils.FormatTransactionID(t.ID),
-		CustomerID: utils.FormatCustomerID(t.CustomerID),
-		AccountID:  utils.FormatAccountID(t.AccountID),
-		Alias:      (Alias)(*t),
+		ID:           utils.FormatTransactionID(t.ID),
+		CustomerID:   utils.FormatCustomerID(t.CustomerID),
+		AccountID:    utils.FormatAccountID(t.AccountID),
+		SignedAmount: t.SignedAmount(),
+		ReversalOf:   reversalOf,
+		Alias:        (Alias)(*t),
 	})
 }
 
 type CreateTransactionRequest struct {
-	CustomerID      int64         `json:"customer_id" validate:"required"`
-	AccountID       int64         `json:"account_id" validate:"required"`
-	Reference       string        `json:"reference"`
-	Amount          float64       `json:"amount" validate:"required"`
-	Status          PaymentStatus `json:"status" validate:"required"`
-	Description     string        `json:"description"`
-	TransactionDate *time.Time    `json:"transaction_date,omitempty"` // If nil, will use NOW() in database
+	CustomerID      int64           `json:"customer_id" validate:"required"`
+	AccountID       int64           `json:"account_id" validate:"required"`
+	Reference       string          `json:"reference"`
+	Type            TransactionType `json:"type" validate:"required"`
+	Direction       Direction       `json:"direction" validate:"required"`
+	Amount          Money           `json:"amount" validate:"required"`
+	Status          PaymentStatus   `json:"status" validate:"required"`
+	Description     string          `json:"description"`
+	ReversalOf      *int64          `json:"reversal_of,omitempty"`
+	TransactionDate *time.Time      `json:"transaction_date,omitempty"` // If nil, will use NOW() in database
+}
+
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// TransactionFilter narrows a transaction listing; nil fields are ignored
+type TransactionFilter struct {
+	Type      *TransactionType
+	Direction *Direction
 }
 
 type UpdateTransactionRequest struct {
-	Amount      *float64       `json:"amount,omitempty"`
+	Amount      *Money         `json:"amount,omitempty"`
 	Status      *PaymentStatus `json:"status,omitempty"`
 	Description *string        `json:"description,omitempty"`
 }
 
+// PaymentNotificationRequest is the canonical payment notification. The
+// default provider sends it as is; other providers' payloads are translated
+// into it by their adapter, which also sets Provider.
 type PaymentNotificationRequest struct {
+	Provider             string `json:"-"`
 	CustomerID           string `json:"customer_id" validate:"required"`
 	PaymentStatus        string `json:"payment_status" validate:"required"`
 	TransactionAmount    string `json:"transaction_amount" validate:"required"`
@@ -0,0 +1,170 @@
+// Package pdf writes simple text-and-line PDF documents using the standard
+// Type 1 fonts, which every PDF reader provides, so no font files or external
+// services are needed.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// Font is one of the standard fonts available in every document
+type Font string
+
+const (
+	Helvetica     Font = "F1"
+	HelveticaBold Font = "F2"
+	Courier       Font = "F3"
+)
+
+var fontNames = []struct {
+	font Font
+	name string
+}{
+	{Helvetica, "Helvetica"},
+	{HelveticaBold, "Helvetica-Bold"},
+	{Courier, "Courier"},
+}
+
+// courierCharWidth is the advance of every Courier glyph per point of font size
+const courierCharWidth = 0.6
+
+// Document is a PDF under construction. Coordinates are in points from the
+// top left corner of the page.
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page; later drawing calls go to it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws text with its baseline starting at (x, y)
+func (d *Document) Text(font Font, size, x, y float64, text string) {
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(text))
+}
+
+// TextRight draws Courier text so that it ends at x
+func (d *Document) TextRight(size, x, y float64, text string) {
+	width := float64(len([]rune(text))) * courierCharWidth * size
+	d.Text(Courier, size, x-width, y, text)
+}
+
+// Line draws a thin line from (x1, y1) to (x2, y2)
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// WriteTo writes the complete document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	offsets := []int64{}
+
+	// Objects are numbered in the order they are written: the catalog, the
+	// page tree, the fonts, then a page and its content stream per page
+	object := func(body string) {
+		offsets = append(offsets, cw.n)
+		fmt.Fprintf(cw, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	firstPage := 3 + len(fontNames)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	fonts := make([]string, len(fontNames))
+	for i, f := range fontNames {
+		fonts[i] = fmt.Sprintf("/%s %d 0 R", f.font, 3+i)
+	}
+
+	fmt.Fprint(cw, "%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, f := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+	}
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, strings.Join(fonts, " "), firstPage+2*i+1,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// escape makes text safe inside a PDF string literal. Characters outside
+// Latin-1 cannot be shown with the standard fonts and become '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AccountRepository interface {
@@ -17,15 +16,20 @@ type AccountRepository interface {
 	GetAll() ([]*models.Account, error)
 	Update(id int64, account *models.UpdateAccountRequest) (*models.Account, error)
 	Delete(id int64) error
-	Debit(accountID int64, transactionID int64, amount float64) error
-	Credit(accountID int64, transactionID int64, amount float64) error
+	Debit(accountID int64, transactionID int64, amount models.Money) error
+	Credit(accountID int64, transactionID int64, amount models.Money) error
+	// LockByID selects the account FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.Account, error)
+	// GetBalanceChecks returns every account's stored balance next to the sum
+	// of its posted transactions
+	GetBalanceChecks() ([]*models.BalanceCheck, error)
 }
 
 type accountRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
-func NewAccountRepository(db *pgxpool.Pool) AccountRepository {
+func NewAccountRepository(db DBTX) AccountRepository {
 	return &accountRepository{db: db}
 }
 
@@ -76,7 +80,7 @@ func (r *accountRepository) GetByID(id int64) (*models.Account, error) {
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("account with id %d not found", id)
+		return nil, notFoundf("account with id %d not found", id)
 	}
 
 	if err != nil {
@@ -105,7 +109,7 @@ func (r *accountRepository) GetByCustomerID(customerID int64) (*models.Account,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("account not found for customer_id %d", customerID)
+		return nil, notFoundf("account not found for customer_id %d", customerID)
 	}
 
 	if err != nil {
@@ -178,7 +182,7 @@ func (r *accountRepository) Update(id int64, accountReq *models.UpdateAccountReq
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("account with id %d not found", id)
+		return nil, notFoundf("account with id %d not found", id)
 	}
 
 	if err != nil {
@@ -199,13 +203,13 @@ func (r *accountRepository) Delete(id int64) error {
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("account with id %d not found", id)
+		return notFoundf("account with id %d not found", id)
 	}
 
 	return nil
 }
 
-func (r *accountRepository) Debit(accountID int64, transactionID int64, amount float64) error {
+func (r *accountRepository) Debit(accountID int64, transactionID int64, amount models.Money) error {
 	ctx := context.Background()
 
 	// Start a transaction
@@ -216,7 +220,7 @@ func (r *accountRepository) Debit(accountID int64, transactionID int64, amount f
 	defer tx.Rollback(ctx)
 
 	// Lock the account row for update to prevent race conditions
-	var previousBalance float64
+	var previousBalance models.Money
 	var customerID int64
 	lockQuery := `
 		SELECT balance, customer_id
@@ -226,7 +230,7 @@ func (r *accountRepository) Debit(accountID int64, transactionID int64, amount f
 	`
 	err = tx.QueryRow(ctx, lockQuery, accountID).Scan(&previousBalance, &customerID)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return fmt.Errorf("account with id %d not found", accountID)
+		return notFoundf("account with id %d not found", accountID)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to lock account: %w", err)
@@ -246,6 +250,19 @@ func (r *accountRepository) Debit(accountID int64, transactionID int64, amount f
 		return fmt.Errorf("failed to update account balance: %w", err)
 	}
 
+	// Record the posting in the ledger within the same transaction
+	err = insertLedgerEntry(ctx, tx, &models.LedgerEntry{
+		AccountID:     accountID,
+		TransactionID: transactionID,
+		Direction:     models.DirectionDebit,
+		Amount:        amount,
+		BalanceBefore: previousBalance,
+		BalanceAfter:  newBalance,
+	})
+	if err != nil {
+		return err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -254,7 +271,7 @@ func (r *accountRepository) Debit(accountID int64, transactionID int64, amount f
 	return nil
 }
 
-func (r *accountRepository) Credit(accountID int64, transactionID int64, amount float64) error {
+func (r *accountRepository) Credit(accountID int64, transactionID int64, amount models.Money) error {
 	ctx := context.Background()
 
 	// Start a transaction
@@ -265,7 +282,7 @@ func (r *accountRepository) Credit(accountID int64, transactionID int64, amount
 	defer tx.Rollback(ctx)
 
 	// Lock the account row for update to prevent race conditions
-	var previousBalance float64
+	var previousBalance models.Money
 	var customerID int64
 	lockQuery := `
 		SELECT balance, customer_id
@@ -275,7 +292,7 @@ func (r *accountRepository) Credit(accountID int64, transactionID int64, amount
 	`
 	err = tx.QueryRow(ctx, lockQuery, accountID).Scan(&previousBalance, &customerID)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return fmt.Errorf("account with id %d not found", accountID)
+		return notFoundf("account with id %d not found", accountID)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to lock account: %w", err)
@@ -295,6 +312,19 @@ func (r *accountRepository) Credit(accountID int64, transactionID int64, amount
 		return fmt.Errorf("failed to update account balance: %w", err)
 	}
 
+	// Record the posting in the ledger within the same transaction
+	err = insertLedgerEntry(ctx, tx, &models.LedgerEntry{
+		AccountID:     accountID,
+		TransactionID: transactionID,
+		Direction:     models.DirectionCredit,
+		Amount:        amount,
+		BalanceBefore: previousBalance,
+		BalanceAfter:  newBalance,
+	})
+	if err != nil {
+		return err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -302,3 +332,65 @@ func (r *accountRepository) Credit(accountID int64, transactionID int64, amount
 
 	return nil
 }
+
+func (r *accountRepository) LockByID(id int64) (*models.Account, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, customer_id, balance, created_at, updated_at
+		FROM accounts
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	account := &models.Account{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&account.ID,
+		&account.CustomerID,
+		&account.Balance,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("account with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *accountRepository) GetBalanceChecks() ([]*models.BalanceCheck, error) {
+	ctx := context.Background()
+	query := `
+		SELECT a.id, a.customer_id, a.balance,
+			COALESCE(SUM(` + signedAmount + `) FILTER (WHERE ` + postedCondition + `), 0)
+		FROM accounts a
+		LEFT JOIN transactions t ON t.account_id = a.id
+		GROUP BY a.id, a.customer_id, a.balance
+		ORDER BY a.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := []*models.BalanceCheck{}
+	for rows.Next() {
+		check := &models.BalanceCheck{}
+		if err := rows.Scan(&check.AccountID, &check.CustomerID, &check.Balance, &check.ExpectedBalance); err != nil {
+			return nil, fmt.Errorf("failed to scan balance check: %w", err)
+		}
+		checks = append(checks, check)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating balance checks: %w", err)
+	}
+
+	return checks, nil
+}
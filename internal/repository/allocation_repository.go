@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type AllocationRepository interface {
+	Create(allocation *models.PaymentAllocation) (*models.PaymentAllocation, error)
+	GetByTransactionID(transactionID int64) ([]*models.PaymentAllocation, error)
+	GetByScheduleID(scheduleID int64) ([]*models.PaymentAllocation, error)
+}
+
+type allocationRepository struct {
+	db DBTX
+}
+
+func NewAllocationRepository(db DBTX) AllocationRepository {
+	return &allocationRepository{db: db}
+}
+
+const allocationColumns = `id, transaction_id, schedule_id, installment_id, component, amount, created_at`
+
+func scanAllocation(row pgx.Row) (*models.PaymentAllocation, error) {
+	allocation := &models.PaymentAllocation{}
+	err := row.Scan(
+		&allocation.ID,
+		&allocation.TransactionID,
+		&allocation.ScheduleID,
+		&allocation.InstallmentID,
+		&allocation.Component,
+		&allocation.Amount,
+		&allocation.CreatedAt,
+	)
+	return allocation, err
+}
+
+func (r *allocationRepository) Create(allocationReq *models.PaymentAllocation) (*models.PaymentAllocation, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO payment_allocations (transaction_id, schedule_id, installment_id, component, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING ` + allocationColumns
+
+	allocation, err := scanAllocation(r.db.QueryRow(
+		ctx,
+		query,
+		allocationReq.TransactionID,
+		allocationReq.ScheduleID,
+		allocationReq.InstallmentID,
+		allocationReq.Component,
+		allocationReq.Amount,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment allocation: %w", err)
+	}
+
+	return allocation, nil
+}
+
+func (r *allocationRepository) GetByTransactionID(transactionID int64) ([]*models.PaymentAllocation, error) {
+	query := `SELECT ` + allocationColumns + ` FROM payment_allocations WHERE transaction_id = $1 ORDER BY id ASC`
+	return r.query(query, transactionID)
+}
+
+func (r *allocationRepository) GetByScheduleID(scheduleID int64) ([]*models.PaymentAllocation, error) {
+	query := `SELECT ` + allocationColumns + ` FROM payment_allocations WHERE schedule_id = $1 ORDER BY id ASC`
+	return r.query(query, scheduleID)
+}
+
+func (r *allocationRepository) query(query string, args ...interface{}) ([]*models.PaymentAllocation, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment allocations: %w", err)
+	}
+	defer rows.Close()
+
+	allocations := []*models.PaymentAllocation{}
+	for rows.Next() {
+		allocation, err := scanAllocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment allocation: %w", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating payment allocations: %w", err)
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ArrearsRepository computes arrears from the installments of active schedules
+// that are unpaid past their due date
+type ArrearsRepository interface {
+	// GetByCustomerID returns the customer's arrears as of the given date. A
+	// customer with nothing overdue gets zero arrears in the CURRENT bucket.
+	GetByCustomerID(customerID int64, asOf time.Time) (*models.Arrears, error)
+	// GetAll returns every customer with overdue installments, most overdue first
+	GetAll(asOf time.Time) ([]*models.Arrears, error)
+}
+
+type arrearsRepository struct {
+	db DBTX
+}
+
+func NewArrearsRepository(db DBTX) ArrearsRepository {
+	return &arrearsRepository{db: db}
+}
+
+// arrearsQuery aggregates overdue installments per account; $1 is the as-of date
+const arrearsQuery = `
+	SELECT a.customer_id, a.id, COALESCE(SUM(o.outstanding), 0), COUNT(o.id), MIN(o.due_date)
+	FROM accounts a
+	LEFT JOIN (
+		SELECT i.id, s.account_id, i.due_date,
+			(i.amount_due - i.amount_paid) + (i.fee_due - i.fee_paid) + (i.penalty_due - i.penalty_paid) AS outstanding
+		FROM installments i
+		JOIN repayment_schedules s ON s.id = i.schedule_id
+		WHERE s.status = 'ACTIVE' AND i.status <> 'PAID' AND i.due_date < $1
+	) o ON o.account_id = a.id
+`
+
+func scanArrears(row pgx.Row, asOf time.Time) (*models.Arrears, error) {
+	var (
+		customerID    int64
+		accountID     int64
+		amount        models.Money
+		overdue       int
+		oldestDueDate *time.Time
+	)
+
+	if err := row.Scan(&customerID, &accountID, &amount, &overdue, &oldestDueDate); err != nil {
+		return nil, err
+	}
+
+	return models.NewArrears(customerID, accountID, amount, overdue, oldestDueDate, asOf), nil
+}
+
+func (r *arrearsRepository) GetByCustomerID(customerID int64, asOf time.Time) (*models.Arrears, error) {
+	ctx := context.Background()
+	query := arrearsQuery + `
+		WHERE a.customer_id = $2
+		GROUP BY a.customer_id, a.id
+	`
+
+	arrears, err := scanArrears(r.db.QueryRow(ctx, query, asOf, customerID), asOf)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("account for customer %d not found", customerID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get arrears: %w", err)
+	}
+
+	return arrears, nil
+}
+
+func (r *arrearsRepository) GetAll(asOf time.Time) ([]*models.Arrears, error) {
+	ctx := context.Background()
+	query := arrearsQuery + `
+		GROUP BY a.customer_id, a.id
+		HAVING COUNT(o.id) > 0
+		ORDER BY MIN(o.due_date) ASC, a.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get arrears: %w", err)
+	}
+	defer rows.Close()
+
+	arrears := []*models.Arrears{}
+	for rows.Next() {
+		item, err := scanArrears(rows, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan arrears: %w", err)
+		}
+		arrears = append(arrears, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating arrears: %w", err)
+	}
+
+	return arrears, nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type AssetRepository interface {
+	Create(asset *models.Asset) (*models.Asset, error)
+	GetByID(id int64) (*models.Asset, error)
+	// GetAll lists assets, newest first, optionally only those with status
+	GetAll(status *models.AssetStatus) ([]*models.Asset, error)
+	// LockByID selects the asset FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.Asset, error)
+	// Update stores every editable field of the asset, including its status
+	Update(asset *models.Asset) (*models.Asset, error)
+	UpdateStatus(id int64, status models.AssetStatus) error
+	Delete(id int64) error
+}
+
+type assetRepository struct {
+	db DBTX
+}
+
+func NewAssetRepository(db DBTX) AssetRepository {
+	return &assetRepository{db: db}
+}
+
+const assetColumns = `id, product_id, vin, plate_number, make, model, purchase_cost, status, created_at, updated_at, deleted_at`
+
+func scanAsset(row pgx.Row) (*models.Asset, error) {
+	asset := &models.Asset{}
+	err := row.Scan(
+		&asset.ID,
+		&asset.ProductID,
+		&asset.VIN,
+		&asset.PlateNumber,
+		&asset.Make,
+		&asset.Model,
+		&asset.PurchaseCost,
+		&asset.Status,
+		&asset.CreatedAt,
+		&asset.UpdatedAt,
+		&asset.DeletedAt,
+	)
+	return asset, err
+}
+
+func (r *assetRepository) Create(assetReq *models.Asset) (*models.Asset, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO assets (product_id, vin, plate_number, make, model, purchase_cost, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING ` + assetColumns
+
+	asset, err := scanAsset(r.db.QueryRow(
+		ctx,
+		query,
+		assetReq.ProductID,
+		assetReq.VIN,
+		assetReq.PlateNumber,
+		assetReq.Make,
+		assetReq.Model,
+		assetReq.PurchaseCost,
+		models.AssetStatusInStock,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (r *assetRepository) GetByID(id int64) (*models.Asset, error) {
+	ctx := context.Background()
+	query := `SELECT ` + assetColumns + ` FROM assets WHERE id = $1 AND deleted_at IS NULL`
+
+	asset, err := scanAsset(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("asset with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (r *assetRepository) GetAll(status *models.AssetStatus) ([]*models.Asset, error) {
+	ctx := context.Background()
+	query := `SELECT ` + assetColumns + ` FROM assets WHERE deleted_at IS NULL`
+	args := []interface{}{}
+
+	if status != nil {
+		query += ` AND status = $1`
+		args = append(args, *status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets: %w", err)
+	}
+	defer rows.Close()
+
+	assets := []*models.Asset{}
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assets: %w", err)
+	}
+
+	return assets, nil
+}
+
+func (r *assetRepository) LockByID(id int64) (*models.Asset, error) {
+	ctx := context.Background()
+	query := `SELECT ` + assetColumns + ` FROM assets WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	asset, err := scanAsset(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("asset with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (r *assetRepository) Update(assetReq *models.Asset) (*models.Asset, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE assets
+		SET product_id = $1, vin = $2, plate_number = $3, make = $4, model = $5, purchase_cost = $6, status = $7, updated_at = NOW()
+		WHERE id = $8 AND deleted_at IS NULL
+		RETURNING ` + assetColumns
+
+	asset, err := scanAsset(r.db.QueryRow(
+		ctx,
+		query,
+		assetReq.ProductID,
+		assetReq.VIN,
+		assetReq.PlateNumber,
+		assetReq.Make,
+		assetReq.Model,
+		assetReq.PurchaseCost,
+		assetReq.Status,
+		assetReq.ID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("asset with id %d not found", assetReq.ID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (r *assetRepository) UpdateStatus(id int64, status models.AssetStatus) error {
+	ctx := context.Background()
+	query := `UPDATE assets SET status = $1, updated_at = NOW() WHERE id = $2`
+
+	result, err := r.db.Exec(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update asset status: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("asset with id %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *assetRepository) Delete(id int64) error {
+	ctx := context.Background()
+	query := "UPDATE assets SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete asset: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("asset with id %d not found", id)
+	}
+
+	return nil
+}
@@ -7,22 +7,25 @@ import (
 
 	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type CustomerRepository interface {
 	Create(customer *models.CreateCustomerRequest) (*models.Customer, error)
 	GetByID(id int64) (*models.Customer, error)
+	// GetByIDIncludingDeleted also returns soft-deleted customers
+	GetByIDIncludingDeleted(id int64) (*models.Customer, error)
+	// LockByID selects the customer FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.Customer, error)
 	GetAll() ([]*models.Customer, error)
 	Update(id int64, customer *models.UpdateCustomerRequest) (*models.Customer, error)
 	Delete(id int64) error
 }
 
 type customerRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
-func NewCustomerRepository(db *pgxpool.Pool) CustomerRepository {
+func NewCustomerRepository(db DBTX) CustomerRepository {
 	return &customerRepository{db: db}
 }
 
@@ -78,7 +81,7 @@ func (r *customerRepository) GetByID(id int64) (*models.Customer, error) {
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("customer with id %d not found", id)
+		return nil, notFoundf("customer with id %d not found", id)
 	}
 
 	if err != nil {
@@ -88,6 +91,67 @@ func (r *customerRepository) GetByID(id int64) (*models.Customer, error) {
 	return customer, nil
 }
 
+func (r *customerRepository) GetByIDIncludingDeleted(id int64) (*models.Customer, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, email, first_name, last_name, created_at, updated_at, deleted_at
+		FROM customers
+		WHERE id = $1
+	`
+
+	customer := &models.Customer{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&customer.ID,
+		&customer.Email,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+		&customer.DeletedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("customer with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+func (r *customerRepository) LockByID(id int64) (*models.Customer, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, email, first_name, last_name, created_at, updated_at, deleted_at
+		FROM customers
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	customer := &models.Customer{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&customer.ID,
+		&customer.Email,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+		&customer.DeletedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("customer with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock customer: %w", err)
+	}
+
+	return customer, nil
+}
+
 func (r *customerRepository) GetAll() ([]*models.Customer, error) {
 	ctx := context.Background()
 	query := `
@@ -168,7 +232,7 @@ func (r *customerRepository) Update(id int64, customerReq *models.UpdateCustomer
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("customer with id %d not found", id)
+		return nil, notFoundf("customer with id %d not found", id)
 	}
 
 	if err != nil {
@@ -189,9 +253,8 @@ func (r *customerRepository) Delete(id int64) error {
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("customer with id %d not found", id)
+		return notFoundf("customer with id %d not found", id)
 	}
 
 	return nil
 }
-
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX is implemented by both *pgxpool.Pool and pgx.Tx, so a repository can
+// run against the pool directly or inside a unit of work. Calling Begin on a
+// pgx.Tx starts a savepoint, which lets repository methods that need their own
+// transaction (such as Debit and Credit) nest inside a unit of work.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ErrNotFound is matched (via errors.Is) by every "not found" error returned
+// from a repository
+var ErrNotFound = errors.New("record not found")
+
+type notFoundError struct {
+	msg string
+}
+
+func (e *notFoundError) Error() string {
+	return e.msg
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// notFoundf formats a not found error that matches ErrNotFound
+func notFoundf(format string, args ...any) error {
+	return &notFoundError{msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type DeploymentRepository interface {
+	Create(deployment *models.Deployment) (*models.Deployment, error)
+	GetByID(id int64) (*models.Deployment, error)
+	GetByTransactionID(transactionID int64) (*models.Deployment, error)
+	// GetAll lists deployments, newest first, optionally only those with status
+	GetAll(status *models.DeploymentStatus) ([]*models.Deployment, error)
+	GetByCustomerID(customerID int64) ([]*models.Deployment, error)
+	// GetByAssetID lists the deployments of an asset, newest first
+	GetByAssetID(assetID int64) ([]*models.Deployment, error)
+	// LockByID selects the deployment FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.Deployment, error)
+	// UpdateStatus stores the deployment's status and, when the status is
+	// terminal, sets closed_at and closed_reason. It does not check that the
+	// transition is allowed.
+	UpdateStatus(id int64, status models.DeploymentStatus, reason string) (*models.Deployment, error)
+	// MarkDefaulted moves ACTIVE deployments with an installment that has been
+	// unpaid for at least days as of date to DEFAULTED and returns them
+	MarkDefaulted(date time.Time, days int) ([]*models.Deployment, error)
+	// CureIfCurrent moves a DEFAULTED deployment back to ACTIVE once none of
+	// its installments are overdue as of date, and reports whether it did
+	CureIfCurrent(id int64, date time.Time) (bool, error)
+}
+
+type deploymentRepository struct {
+	db DBTX
+}
+
+func NewDeploymentRepository(db DBTX) DeploymentRepository {
+	return &deploymentRepository{db: db}
+}
+
+const deploymentColumns = `id, customer_id, account_id, product_id, asset_id, transaction_id, reference, amount, status, start_date, end_date, closed_at, closed_reason, created_at, updated_at`
+
+func scanDeployment(row pgx.Row) (*models.Deployment, error) {
+	deployment := &models.Deployment{}
+	err := row.Scan(
+		&deployment.ID,
+		&deployment.CustomerID,
+		&deployment.AccountID,
+		&deployment.ProductID,
+		&deployment.AssetID,
+		&deployment.TransactionID,
+		&deployment.Reference,
+		&deployment.Amount,
+		&deployment.Status,
+		&deployment.StartDate,
+		&deployment.EndDate,
+		&deployment.ClosedAt,
+		&deployment.ClosedReason,
+		&deployment.CreatedAt,
+		&deployment.UpdatedAt,
+	)
+	return deployment, err
+}
+
+func (r *deploymentRepository) Create(deploymentReq *models.Deployment) (*models.Deployment, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO deployments (customer_id, account_id, product_id, asset_id, transaction_id, reference, amount, status, start_date, end_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING ` + deploymentColumns
+
+	deployment, err := scanDeployment(r.db.QueryRow(
+		ctx,
+		query,
+		deploymentReq.CustomerID,
+		deploymentReq.AccountID,
+		deploymentReq.ProductID,
+		deploymentReq.AssetID,
+		deploymentReq.TransactionID,
+		deploymentReq.Reference,
+		deploymentReq.Amount,
+		models.DeploymentStatusActive,
+		deploymentReq.StartDate,
+		deploymentReq.EndDate,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", err)
+	}
+
+	return deployment, nil
+}
+
+func (r *deploymentRepository) GetByID(id int64) (*models.Deployment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + deploymentColumns + ` FROM deployments WHERE id = $1`
+
+	deployment, err := scanDeployment(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("deployment with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	return deployment, nil
+}
+
+func (r *deploymentRepository) GetByTransactionID(transactionID int64) (*models.Deployment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + deploymentColumns + ` FROM deployments WHERE transaction_id = $1`
+
+	deployment, err := scanDeployment(r.db.QueryRow(ctx, query, transactionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("deployment for transaction %d not found", transactionID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	return deployment, nil
+}
+
+func (r *deploymentRepository) GetAll(status *models.DeploymentStatus) ([]*models.Deployment, error) {
+	query := `SELECT ` + deploymentColumns + ` FROM deployments`
+	args := []interface{}{}
+
+	if status != nil {
+		query += ` WHERE status = $1`
+		args = append(args, *status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	return r.query(query, args...)
+}
+
+func (r *deploymentRepository) GetByCustomerID(customerID int64) ([]*models.Deployment, error) {
+	query := `SELECT ` + deploymentColumns + ` FROM deployments WHERE customer_id = $1 ORDER BY created_at DESC`
+	return r.query(query, customerID)
+}
+
+func (r *deploymentRepository) GetByAssetID(assetID int64) ([]*models.Deployment, error) {
+	query := `SELECT ` + deploymentColumns + ` FROM deployments WHERE asset_id = $1 ORDER BY created_at DESC`
+	return r.query(query, assetID)
+}
+
+func (r *deploymentRepository) LockByID(id int64) (*models.Deployment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + deploymentColumns + ` FROM deployments WHERE id = $1 FOR UPDATE`
+
+	deployment, err := scanDeployment(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("deployment with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock deployment: %w", err)
+	}
+
+	return deployment, nil
+}
+
+func (r *deploymentRepository) UpdateStatus(id int64, status models.DeploymentStatus, reason string) (*models.Deployment, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE deployments
+		SET status = $1,
+			closed_at = CASE WHEN $2::boolean THEN NOW() END,
+			closed_reason = CASE WHEN $2::boolean THEN NULLIF($3, '') END,
+			updated_at = NOW()
+		WHERE id = $4
+		RETURNING ` + deploymentColumns
+
+	deployment, err := scanDeployment(r.db.QueryRow(ctx, query, status, status.IsTerminal(), reason, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("deployment with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update deployment status: %w", err)
+	}
+
+	return deployment, nil
+}
+
+func (r *deploymentRepository) MarkDefaulted(date time.Time, days int) ([]*models.Deployment, error) {
+	query := `
+		UPDATE deployments d
+		SET status = $1, updated_at = NOW()
+		WHERE d.status = $2
+			AND EXISTS (
+				SELECT 1
+				FROM repayment_schedules s
+				JOIN installments i ON i.schedule_id = s.id
+				WHERE s.deployment_id = d.id AND s.status = $3 AND i.status <> $4 AND i.due_date <= $5::date - $6::int
+			)
+		RETURNING ` + deploymentColumns
+
+	return r.query(query, models.DeploymentStatusDefaulted, models.DeploymentStatusActive, models.ScheduleStatusActive, models.InstallmentStatusPaid, date, days)
+}
+
+func (r *deploymentRepository) CureIfCurrent(id int64, date time.Time) (bool, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE deployments d
+		SET status = $1, updated_at = NOW()
+		WHERE d.id = $2
+			AND d.status = $3
+			AND NOT EXISTS (
+				SELECT 1
+				FROM repayment_schedules s
+				JOIN installments i ON i.schedule_id = s.id
+				WHERE s.deployment_id = d.id AND i.status <> $4 AND i.due_date < $5::date
+			)
+	`
+
+	result, err := r.db.Exec(ctx, query, models.DeploymentStatusActive, id, models.DeploymentStatusDefaulted, models.InstallmentStatusPaid, date)
+	if err != nil {
+		return false, fmt.Errorf("failed to cure deployment: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *deploymentRepository) query(query string, args ...interface{}) ([]*models.Deployment, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployments: %w", err)
+	}
+	defer rows.Close()
+
+	deployments := []*models.Deployment{}
+	for rows.Next() {
+		deployment, err := scanDeployment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deployments: %w", err)
+	}
+
+	return deployments, nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GuarantorRepository looks guarantors up within their customer, so a
+// guarantor ID belonging to another customer is not found
+type GuarantorRepository interface {
+	Create(guarantor *models.Guarantor) (*models.Guarantor, error)
+	GetByID(customerID, id int64) (*models.Guarantor, error)
+	GetByCustomerID(customerID int64) ([]*models.Guarantor, error)
+	// CountVerified counts the customer's verified guarantors
+	CountVerified(customerID int64) (int, error)
+	// LockByID selects the guarantor FOR UPDATE; it must run inside a unit of work
+	LockByID(customerID, id int64) (*models.Guarantor, error)
+	// Update stores the guarantor's details and verification
+	Update(guarantor *models.Guarantor) (*models.Guarantor, error)
+	// Verify sets verified_at and records who verified the guarantor
+	Verify(customerID, id int64, actor string) (*models.Guarantor, error)
+	Delete(customerID, id int64) error
+}
+
+type guarantorRepository struct {
+	db DBTX
+}
+
+func NewGuarantorRepository(db DBTX) GuarantorRepository {
+	return &guarantorRepository{db: db}
+}
+
+const guarantorColumns = `id, customer_id, first_name, last_name, phone_number, email, address, relationship, verified_at, verified_by, created_at, updated_at, deleted_at`
+
+func scanGuarantor(row pgx.Row) (*models.Guarantor, error) {
+	guarantor := &models.Guarantor{}
+	err := row.Scan(
+		&guarantor.ID,
+		&guarantor.CustomerID,
+		&guarantor.FirstName,
+		&guarantor.LastName,
+		&guarantor.PhoneNumber,
+		&guarantor.Email,
+		&guarantor.Address,
+		&guarantor.Relationship,
+		&guarantor.VerifiedAt,
+		&guarantor.VerifiedBy,
+		&guarantor.CreatedAt,
+		&guarantor.UpdatedAt,
+		&guarantor.DeletedAt,
+	)
+	return guarantor, err
+}
+
+func (r *guarantorRepository) Create(guarantorReq *models.Guarantor) (*models.Guarantor, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO guarantors (customer_id, first_name, last_name, phone_number, email, address, relationship, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING ` + guarantorColumns
+
+	guarantor, err := scanGuarantor(r.db.QueryRow(
+		ctx,
+		query,
+		guarantorReq.CustomerID,
+		guarantorReq.FirstName,
+		guarantorReq.LastName,
+		guarantorReq.PhoneNumber,
+		guarantorReq.Email,
+		guarantorReq.Address,
+		guarantorReq.Relationship,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create guarantor: %w", err)
+	}
+
+	return guarantor, nil
+}
+
+func (r *guarantorRepository) GetByID(customerID, id int64) (*models.Guarantor, error) {
+	ctx := context.Background()
+	query := `SELECT ` + guarantorColumns + ` FROM guarantors WHERE id = $1 AND customer_id = $2 AND deleted_at IS NULL`
+
+	guarantor, err := scanGuarantor(r.db.QueryRow(ctx, query, id, customerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("guarantor with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guarantor: %w", err)
+	}
+
+	return guarantor, nil
+}
+
+func (r *guarantorRepository) GetByCustomerID(customerID int64) ([]*models.Guarantor, error) {
+	ctx := context.Background()
+	query := `SELECT ` + guarantorColumns + ` FROM guarantors WHERE customer_id = $1 AND deleted_at IS NULL ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guarantors: %w", err)
+	}
+	defer rows.Close()
+
+	guarantors := []*models.Guarantor{}
+	for rows.Next() {
+		guarantor, err := scanGuarantor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan guarantor: %w", err)
+		}
+		guarantors = append(guarantors, guarantor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating guarantors: %w", err)
+	}
+
+	return guarantors, nil
+}
+
+func (r *guarantorRepository) CountVerified(customerID int64) (int, error) {
+	ctx := context.Background()
+	query := `SELECT COUNT(*) FROM guarantors WHERE customer_id = $1 AND verified_at IS NOT NULL AND deleted_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, customerID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count verified guarantors: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *guarantorRepository) LockByID(customerID, id int64) (*models.Guarantor, error) {
+	ctx := context.Background()
+	query := `SELECT ` + guarantorColumns + ` FROM guarantors WHERE id = $1 AND customer_id = $2 AND deleted_at IS NULL FOR UPDATE`
+
+	guarantor, err := scanGuarantor(r.db.QueryRow(ctx, query, id, customerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("guarantor with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock guarantor: %w", err)
+	}
+
+	return guarantor, nil
+}
+
+func (r *guarantorRepository) Update(guarantorReq *models.Guarantor) (*models.Guarantor, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE guarantors
+		SET first_name = $1, last_name = $2, phone_number = $3, email = $4, address = $5, relationship = $6, verified_at = $7, verified_by = $8, updated_at = NOW()
+		WHERE id = $9 AND customer_id = $10 AND deleted_at IS NULL
+		RETURNING ` + guarantorColumns
+
+	guarantor, err := scanGuarantor(r.db.QueryRow(
+		ctx,
+		query,
+		guarantorReq.FirstName,
+		guarantorReq.LastName,
+		guarantorReq.PhoneNumber,
+		guarantorReq.Email,
+		guarantorReq.Address,
+		guarantorReq.Relationship,
+		guarantorReq.VerifiedAt,
+		guarantorReq.VerifiedBy,
+		guarantorReq.ID,
+		guarantorReq.CustomerID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("guarantor with id %d not found", guarantorReq.ID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update guarantor: %w", err)
+	}
+
+	return guarantor, nil
+}
+
+func (r *guarantorRepository) Verify(customerID, id int64, actor string) (*models.Guarantor, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE guarantors
+		SET verified_at = NOW(), verified_by = $1, updated_at = NOW()
+		WHERE id = $2 AND customer_id = $3 AND deleted_at IS NULL
+		RETURNING ` + guarantorColumns
+
+	guarantor, err := scanGuarantor(r.db.QueryRow(ctx, query, actor, id, customerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("guarantor with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify guarantor: %w", err)
+	}
+
+	return guarantor, nil
+}
+
+func (r *guarantorRepository) Delete(customerID, id int64) error {
+	ctx := context.Background()
+	query := "UPDATE guarantors SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND customer_id = $2 AND deleted_at IS NULL"
+
+	result, err := r.db.Exec(ctx, query, id, customerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete guarantor: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("guarantor with id %d not found", id)
+	}
+
+	return nil
+}
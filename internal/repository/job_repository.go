@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type JobRepository interface {
+	Enqueue(queue string, payload []byte, maxAttempts int) (*models.Job, error)
+	// ClaimNext locks the next runnable job on the queue and marks it RUNNING.
+	// Jobs left RUNNING for longer than lockTimeout (e.g. after a crash) are
+	// claimed again. It returns nil when no job is ready.
+	ClaimNext(queue string, lockTimeout time.Duration) (*models.Job, error)
+	Complete(id int64) error
+	Retry(id int64, runAt time.Time, reason string) error
+	Bury(id int64, reason string) error
+}
+
+type jobRepository struct {
+	db DBTX
+}
+
+func NewJobRepository(db DBTX) JobRepository {
+	return &jobRepository{db: db}
+}
+
+const jobColumns = `id, queue, payload, status, attempts, max_attempts, run_at, locked_at, last_error, created_at, updated_at`
+
+func scanJob(row pgx.Row) (*models.Job, error) {
+	job := &models.Job{}
+	err := row.Scan(
+		&job.ID,
+		&job.Queue,
+		&job.Payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedAt,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	return job, err
+}
+
+func (r *jobRepository) Enqueue(queue string, payload []byte, maxAttempts int) (*models.Job, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO jobs (queue, payload, status, max_attempts, run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW(), NOW())
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRow(ctx, query, queue, payload, models.JobStatusPending, maxAttempts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) ClaimNext(queue string, lockTimeout time.Duration) (*models.Job, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE jobs
+		SET status = $1, attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM jobs
+			WHERE queue = $2
+				AND (
+					(status = $3 AND run_at <= NOW())
+					OR (status = $1 AND locked_at < NOW() - make_interval(secs => $4))
+				)
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRow(
+		ctx,
+		query,
+		models.JobStatusRunning,
+		queue,
+		models.JobStatusPending,
+		lockTimeout.Seconds(),
+	))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) Complete(id int64) error {
+	ctx := context.Background()
+	query := `
+		UPDATE jobs
+		SET status = $1, locked_at = NULL, last_error = NULL, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	return r.exec(ctx, query, models.JobStatusDone, id)
+}
+
+func (r *jobRepository) Retry(id int64, runAt time.Time, reason string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE jobs
+		SET status = $1, run_at = $2, last_error = $3, locked_at = NULL, updated_at = NOW()
+		WHERE id = $4
+	`
+
+	return r.exec(ctx, query, models.JobStatusPending, runAt, reason, id)
+}
+
+func (r *jobRepository) Bury(id int64, reason string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE jobs
+		SET status = $1, last_error = $2, locked_at = NULL, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	return r.exec(ctx, query, models.JobStatusDead, reason, id)
+}
+
+func (r *jobRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("job not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type LedgerRepository interface {
+	GetByAccountID(accountID int64) ([]*models.LedgerEntry, error)
+	GetByCustomerID(customerID int64) ([]*models.LedgerEntry, error)
+	GetByTransactionID(transactionID int64) ([]*models.LedgerEntry, error)
+}
+
+type ledgerRepository struct {
+	db DBTX
+}
+
+func NewLedgerRepository(db DBTX) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+// insertLedgerEntry writes a ledger entry inside the database transaction that moved the balance
+func insertLedgerEntry(ctx context.Context, tx pgx.Tx, entry *models.LedgerEntry) error {
+	query := `
+		INSERT INTO ledger_entries (account_id, transaction_id, direction, amount, balance_before, balance_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRow(
+		ctx,
+		query,
+		entry.AccountID,
+		entry.TransactionID,
+		entry.Direction,
+		entry.Amount,
+		entry.BalanceBefore,
+		entry.BalanceAfter,
+	).Scan(&entry.ID, &entry.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create ledger entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ledgerRepository) GetByAccountID(accountID int64) ([]*models.LedgerEntry, error) {
+	query := `
+		SELECT id, account_id, transaction_id, direction, amount, balance_before, balance_after, created_at
+		FROM ledger_entries
+		WHERE account_id = $1
+		ORDER BY id DESC
+	`
+
+	return r.query(query, accountID)
+}
+
+func (r *ledgerRepository) GetByCustomerID(customerID int64) ([]*models.LedgerEntry, error) {
+	query := `
+		SELECT l.id, l.account_id, l.transaction_id, l.direction, l.amount, l.balance_before, l.balance_after, l.created_at
+		FROM ledger_entries l
+		JOIN accounts a ON a.id = l.account_id
+		WHERE a.customer_id = $1
+		ORDER BY l.id DESC
+	`
+
+	return r.query(query, customerID)
+}
+
+func (r *ledgerRepository) GetByTransactionID(transactionID int64) ([]*models.LedgerEntry, error) {
+	query := `
+		SELECT id, account_id, transaction_id, direction, amount, balance_before, balance_after, created_at
+		FROM ledger_entries
+		WHERE transaction_id = $1
+		ORDER BY id ASC
+	`
+
+	return r.query(query, transactionID)
+}
+
+func (r *ledgerRepository) query(query string, args ...interface{}) ([]*models.LedgerEntry, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.LedgerEntry{}
+	for rows.Next() {
+		entry := &models.LedgerEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.AccountID,
+			&entry.TransactionID,
+			&entry.Direction,
+			&entry.Amount,
+			&entry.BalanceBefore,
+			&entry.BalanceAfter,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ledger entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type PaymentNotificationRepository interface {
+	// Create stores the notification unless one with the same provider,
+	// reference and payment status already exists. It returns the stored notification and
+	// whether this call inserted it.
+	Create(notification *models.PaymentNotification) (*models.PaymentNotification, bool, error)
+	GetByID(id int64) (*models.PaymentNotification, error)
+	// LockByID selects the notification FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.PaymentNotification, error)
+	GetByReference(provider, reference string, paymentStatus models.PaymentStatus) (*models.PaymentNotification, error)
+	MarkProcessed(id int64, accountID int64, transactionID int64) error
+	MarkFailed(id int64, reason string) error
+}
+
+type paymentNotificationRepository struct {
+	db DBTX
+}
+
+func NewPaymentNotificationRepository(db DBTX) PaymentNotificationRepository {
+	return &paymentNotificationRepository{db: db}
+}
+
+const paymentNotificationColumns = `id, provider, reference, customer_ref, account_id, transaction_id, payment_status, amount, transaction_date, status, error, created_at, updated_at`
+
+func scanPaymentNotification(row pgx.Row) (*models.PaymentNotification, error) {
+	notification := &models.PaymentNotification{}
+	err := row.Scan(
+		&notification.ID,
+		&notification.Provider,
+		&notification.Reference,
+		&notification.CustomerRef,
+		&notification.AccountID,
+		&notification.TransactionID,
+		&notification.PaymentStatus,
+		&notification.Amount,
+		&notification.TransactionDate,
+		&notification.Status,
+		&notification.Error,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	return notification, err
+}
+
+func (r *paymentNotificationRepository) Create(notificationReq *models.PaymentNotification) (*models.PaymentNotification, bool, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO payment_notifications (provider, reference, customer_ref, payment_status, amount, transaction_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (provider, reference, payment_status) DO NOTHING
+		RETURNING ` + paymentNotificationColumns
+
+	notification, err := scanPaymentNotification(r.db.QueryRow(
+		ctx,
+		query,
+		notificationReq.Provider,
+		notificationReq.Reference,
+		notificationReq.CustomerRef,
+		notificationReq.PaymentStatus,
+		notificationReq.Amount,
+		notificationReq.TransactionDate,
+		models.NotificationStatusReceived,
+	))
+
+	// Nothing was inserted, so the notification has been seen before
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, err := r.GetByReference(notificationReq.Provider, notificationReq.Reference, notificationReq.PaymentStatus)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create payment notification: %w", err)
+	}
+
+	return notification, true, nil
+}
+
+func (r *paymentNotificationRepository) GetByID(id int64) (*models.PaymentNotification, error) {
+	ctx := context.Background()
+	query := `SELECT ` + paymentNotificationColumns + ` FROM payment_notifications WHERE id = $1`
+
+	notification, err := scanPaymentNotification(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("payment notification with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (r *paymentNotificationRepository) LockByID(id int64) (*models.PaymentNotification, error) {
+	ctx := context.Background()
+	query := `SELECT ` + paymentNotificationColumns + ` FROM payment_notifications WHERE id = $1 FOR UPDATE`
+
+	notification, err := scanPaymentNotification(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("payment notification with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock payment notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (r *paymentNotificationRepository) GetByReference(provider, reference string, paymentStatus models.PaymentStatus) (*models.PaymentNotification, error) {
+	ctx := context.Background()
+	query := `SELECT ` + paymentNotificationColumns + ` FROM payment_notifications WHERE provider = $1 AND reference = $2 AND payment_status = $3`
+
+	notification, err := scanPaymentNotification(r.db.QueryRow(ctx, query, provider, reference, paymentStatus))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("payment notification from %s with reference %s and status %s not found", provider, reference, paymentStatus)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (r *paymentNotificationRepository) MarkProcessed(id int64, accountID int64, transactionID int64) error {
+	ctx := context.Background()
+	query := `
+		UPDATE payment_notifications
+		SET status = $1, account_id = $2, transaction_id = $3, error = NULL, updated_at = NOW()
+		WHERE id = $4
+	`
+
+	result, err := r.db.Exec(ctx, query, models.NotificationStatusProcessed, accountID, transactionID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update payment notification: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("payment notification with id %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *paymentNotificationRepository) MarkFailed(id int64, reason string) error {
+	ctx := context.Background()
+	query := `
+		UPDATE payment_notifications
+		SET status = $1, error = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, models.NotificationStatusFailed, reason, id)
+	if err != nil {
+		return fmt.Errorf("failed to update payment notification: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("payment notification with id %d not found", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type PenaltyRepository interface {
+	CreateRule(rule *models.PenaltyRule) (*models.PenaltyRule, error)
+	GetRuleByID(id int64) (*models.PenaltyRule, error)
+	GetRules() ([]*models.PenaltyRule, error)
+	GetActiveRules() ([]*models.PenaltyRule, error)
+	UpdateRule(id int64, rule *models.UpdatePenaltyRuleRequest) (*models.PenaltyRule, error)
+	DeleteRule(id int64) error
+
+	// GetOverdueInstallments returns the unpaid installments of active schedules
+	// that were due before date
+	GetOverdueInstallments(date time.Time) ([]*models.OverdueInstallment, error)
+	// HasCharge reports whether the installment was already charged on date
+	HasCharge(installmentID int64, date time.Time) (bool, error)
+	// GetChargedTotals returns the penalties charged so far on the installment
+	// and on its whole schedule, leaving out reversed charges
+	GetChargedTotals(installmentID, scheduleID int64) (installmentTotal, scheduleTotal models.Money, err error)
+	CreateCharge(charge *models.PenaltyCharge) (*models.PenaltyCharge, error)
+	// GetChargeByTransactionID returns the charge posted by a FEE transaction
+	GetChargeByTransactionID(transactionID int64) (*models.PenaltyCharge, error)
+	// MarkChargeReversed flags a charge whose FEE transaction was reversed
+	MarkChargeReversed(id int64) error
+}
+
+type penaltyRepository struct {
+	db DBTX
+}
+
+func NewPenaltyRepository(db DBTX) PenaltyRepository {
+	return &penaltyRepository{db: db}
+}
+
+const penaltyChargeColumns = `id, rule_id, schedule_id, installment_id, transaction_id, charge_date, amount, reversed_at, created_at`
+
+const penaltyRuleColumns = `id, name, type, amount, rate_bps, grace_days, installment_cap, deployment_cap, product_id, active, created_at, updated_at`
+
+func scanPenaltyRule(row pgx.Row) (*models.PenaltyRule, error) {
+	rule := &models.PenaltyRule{}
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Type,
+		&rule.Amount,
+		&rule.RateBps,
+		&rule.GraceDays,
+		&rule.InstallmentCap,
+		&rule.DeploymentCap,
+		&rule.ProductID,
+		&rule.Active,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	return rule, err
+}
+
+func scanPenaltyCharge(row pgx.Row) (*models.PenaltyCharge, error) {
+	charge := &models.PenaltyCharge{}
+	err := row.Scan(
+		&charge.ID,
+		&charge.RuleID,
+		&charge.ScheduleID,
+		&charge.InstallmentID,
+		&charge.TransactionID,
+		&charge.ChargeDate,
+		&charge.Amount,
+		&charge.ReversedAt,
+		&charge.CreatedAt,
+	)
+	return charge, err
+}
+
+func (r *penaltyRepository) CreateRule(ruleReq *models.PenaltyRule) (*models.PenaltyRule, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO penalty_rules (name, type, amount, rate_bps, grace_days, installment_cap, deployment_cap, product_id, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, TRUE, NOW(), NOW())
+		RETURNING ` + penaltyRuleColumns
+
+	rule, err := scanPenaltyRule(r.db.QueryRow(
+		ctx,
+		query,
+		ruleReq.Name,
+		ruleReq.Type,
+		ruleReq.Amount,
+		ruleReq.RateBps,
+		ruleReq.GraceDays,
+		ruleReq.InstallmentCap,
+		ruleReq.DeploymentCap,
+		ruleReq.ProductID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create penalty rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *penaltyRepository) GetRuleByID(id int64) (*models.PenaltyRule, error) {
+	ctx := context.Background()
+	query := `SELECT ` + penaltyRuleColumns + ` FROM penalty_rules WHERE id = $1`
+
+	rule, err := scanPenaltyRule(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("penalty rule with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penalty rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *penaltyRepository) GetRules() ([]*models.PenaltyRule, error) {
+	query := `SELECT ` + penaltyRuleColumns + ` FROM penalty_rules ORDER BY created_at DESC`
+	return r.queryRules(query)
+}
+
+func (r *penaltyRepository) GetActiveRules() ([]*models.PenaltyRule, error) {
+	query := `SELECT ` + penaltyRuleColumns + ` FROM penalty_rules WHERE active ORDER BY created_at DESC, id DESC`
+	return r.queryRules(query)
+}
+
+func (r *penaltyRepository) queryRules(query string, args ...interface{}) ([]*models.PenaltyRule, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penalty rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*models.PenaltyRule{}
+	for rows.Next() {
+		rule, err := scanPenaltyRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan penalty rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating penalty rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *penaltyRepository) UpdateRule(id int64, ruleReq *models.UpdatePenaltyRuleRequest) (*models.PenaltyRule, error) {
+	ctx := context.Background()
+	// Build dynamic update query
+	query := "UPDATE penalty_rules SET updated_at = NOW()"
+	args := []interface{}{}
+	argPos := 1
+
+	if ruleReq.Name != nil {
+		query += fmt.Sprintf(", name = $%d", argPos)
+		args = append(args, *ruleReq.Name)
+		argPos++
+	}
+
+	if ruleReq.Amount != nil {
+		query += fmt.Sprintf(", amount = $%d", argPos)
+		args = append(args, *ruleReq.Amount)
+		argPos++
+	}
+
+	if ruleReq.RateBps != nil {
+		query += fmt.Sprintf(", rate_bps = $%d", argPos)
+		args = append(args, *ruleReq.RateBps)
+		argPos++
+	}
+
+	if ruleReq.GraceDays != nil {
+		query += fmt.Sprintf(", grace_days = $%d", argPos)
+		args = append(args, *ruleReq.GraceDays)
+		argPos++
+	}
+
+	if ruleReq.InstallmentCap != nil {
+		query += fmt.Sprintf(", installment_cap = $%d", argPos)
+		args = append(args, *ruleReq.InstallmentCap)
+		argPos++
+	}
+
+	if ruleReq.DeploymentCap != nil {
+		query += fmt.Sprintf(", deployment_cap = $%d", argPos)
+		args = append(args, *ruleReq.DeploymentCap)
+		argPos++
+	}
+
+	if ruleReq.Active != nil {
+		query += fmt.Sprintf(", active = $%d", argPos)
+		args = append(args, *ruleReq.Active)
+		argPos++
+	}
+
+	query += fmt.Sprintf(" WHERE id = $%d RETURNING %s", argPos, penaltyRuleColumns)
+	args = append(args, id)
+
+	rule, err := scanPenaltyRule(r.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("penalty rule with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update penalty rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *penaltyRepository) DeleteRule(id int64) error {
+	ctx := context.Background()
+	query := "DELETE FROM penalty_rules WHERE id = $1"
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete penalty rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("penalty rule with id %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *penaltyRepository) GetOverdueInstallments(date time.Time) ([]*models.OverdueInstallment, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + prefixColumns("i", installmentColumns) + `, s.customer_id, s.account_id, s.product_id
+		FROM installments i
+		JOIN repayment_schedules s ON s.id = i.schedule_id
+		WHERE s.status = $1 AND i.status <> $2 AND i.due_date < $3::date
+		ORDER BY i.due_date ASC, i.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, models.ScheduleStatusActive, models.InstallmentStatusPaid, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue installments: %w", err)
+	}
+	defer rows.Close()
+
+	overdue := []*models.OverdueInstallment{}
+	for rows.Next() {
+		installment := &models.Installment{}
+		item := &models.OverdueInstallment{Installment: installment}
+		err := rows.Scan(
+			&installment.ID,
+			&installment.ScheduleID,
+			&installment.Sequence,
+			&installment.DueDate,
+			&installment.AmountDue,
+			&installment.AmountPaid,
+			&installment.FeeDue,
+			&installment.FeePaid,
+			&installment.PenaltyDue,
+			&installment.PenaltyPaid,
+			&installment.Status,
+			&installment.PaidAt,
+			&installment.CreatedAt,
+			&installment.UpdatedAt,
+			&item.CustomerID,
+			&item.AccountID,
+			&item.ProductID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan overdue installment: %w", err)
+		}
+		overdue = append(overdue, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating overdue installments: %w", err)
+	}
+
+	return overdue, nil
+}
+
+func (r *penaltyRepository) HasCharge(installmentID int64, date time.Time) (bool, error) {
+	ctx := context.Background()
+	query := `SELECT EXISTS (SELECT 1 FROM penalty_charges WHERE installment_id = $1 AND charge_date = $2::date)`
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, installmentID, date).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check penalty charge: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *penaltyRepository) GetChargedTotals(installmentID, scheduleID int64) (models.Money, models.Money, error) {
+	ctx := context.Background()
+	query := `
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE installment_id = $1), 0),
+			COALESCE(SUM(amount), 0)
+		FROM penalty_charges
+		WHERE schedule_id = $2 AND reversed_at IS NULL
+	`
+
+	var installmentTotal, scheduleTotal models.Money
+	if err := r.db.QueryRow(ctx, query, installmentID, scheduleID).Scan(&installmentTotal, &scheduleTotal); err != nil {
+		return 0, 0, fmt.Errorf("failed to get penalty totals: %w", err)
+	}
+
+	return installmentTotal, scheduleTotal, nil
+}
+
+func (r *penaltyRepository) CreateCharge(chargeReq *models.PenaltyCharge) (*models.PenaltyCharge, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO penalty_charges (rule_id, schedule_id, installment_id, transaction_id, charge_date, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING ` + penaltyChargeColumns
+
+	charge, err := scanPenaltyCharge(r.db.QueryRow(
+		ctx,
+		query,
+		chargeReq.RuleID,
+		chargeReq.ScheduleID,
+		chargeReq.InstallmentID,
+		chargeReq.TransactionID,
+		chargeReq.ChargeDate,
+		chargeReq.Amount,
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create penalty charge: %w", err)
+	}
+
+	return charge, nil
+}
+
+func (r *penaltyRepository) GetChargeByTransactionID(transactionID int64) (*models.PenaltyCharge, error) {
+	ctx := context.Background()
+	query := `SELECT ` + penaltyChargeColumns + ` FROM penalty_charges WHERE transaction_id = $1`
+
+	charge, err := scanPenaltyCharge(r.db.QueryRow(ctx, query, transactionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("penalty charge for transaction %d not found", transactionID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penalty charge: %w", err)
+	}
+
+	return charge, nil
+}
+
+func (r *penaltyRepository) MarkChargeReversed(id int64) error {
+	ctx := context.Background()
+	query := `UPDATE penalty_charges SET reversed_at = NOW() WHERE id = $1 AND reversed_at IS NULL`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to reverse penalty charge: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("penalty charge with id %d not found", id)
+	}
+
+	return nil
+}
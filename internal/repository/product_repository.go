@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type ProductRepository interface {
+	Create(product *models.CreateProductRequest) (*models.Product, error)
+	GetByID(id int64) (*models.Product, error)
+	GetAll() ([]*models.Product, error)
+	Update(id int64, product *models.UpdateProductRequest) (*models.Product, error)
+	Delete(id int64) error
+}
+
+type productRepository struct {
+	db DBTX
+}
+
+func NewProductRepository(db DBTX) ProductRepository {
+	return &productRepository{db: db}
+}
+
+func (r *productRepository) Create(productReq *models.CreateProductRequest) (*models.Product, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO products (name, asset_type, price, tenor, frequency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, name, asset_type, price, tenor, frequency, created_at, updated_at, deleted_at
+	`
+
+	product := &models.Product{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		productReq.Name,
+		productReq.AssetType,
+		productReq.Price,
+		productReq.Tenor,
+		productReq.Frequency,
+	).Scan(
+		&product.ID,
+		&product.Name,
+		&product.AssetType,
+		&product.Price,
+		&product.Tenor,
+		&product.Frequency,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.DeletedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) GetByID(id int64) (*models.Product, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, name, asset_type, price, tenor, frequency, created_at, updated_at, deleted_at
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	product := &models.Product{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&product.ID,
+		&product.Name,
+		&product.AssetType,
+		&product.Price,
+		&product.Tenor,
+		&product.Frequency,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.DeletedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("product with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) GetAll() ([]*models.Product, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, name, asset_type, price, tenor, frequency, created_at, updated_at, deleted_at
+		FROM products
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []*models.Product{}
+	for rows.Next() {
+		product := &models.Product{}
+		err := rows.Scan(
+			&product.ID,
+			&product.Name,
+			&product.AssetType,
+			&product.Price,
+			&product.Tenor,
+			&product.Frequency,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+			&product.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	return products, nil
+}
+
+func (r *productRepository) Update(id int64, productReq *models.UpdateProductRequest) (*models.Product, error) {
+	ctx := context.Background()
+	// Build dynamic update query
+	query := "UPDATE products SET updated_at = NOW()"
+	args := []interface{}{}
+	argPos := 1
+
+	if productReq.Name != nil {
+		query += fmt.Sprintf(", name = $%d", argPos)
+		args = append(args, *productReq.Name)
+		argPos++
+	}
+
+	if productReq.AssetType != nil {
+		query += fmt.Sprintf(", asset_type = $%d", argPos)
+		args = append(args, *productReq.AssetType)
+		argPos++
+	}
+
+	if productReq.Price != nil {
+		query += fmt.Sprintf(", price = $%d", argPos)
+		args = append(args, *productReq.Price)
+		argPos++
+	}
+
+	if productReq.Tenor != nil {
+		query += fmt.Sprintf(", tenor = $%d", argPos)
+		args = append(args, *productReq.Tenor)
+		argPos++
+	}
+
+	if productReq.Frequency != nil {
+		query += fmt.Sprintf(", frequency = $%d", argPos)
+		args = append(args, *productReq.Frequency)
+		argPos++
+	}
+
+	query += fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL RETURNING id, name, asset_type, price, tenor, frequency, created_at, updated_at, deleted_at", argPos)
+	args = append(args, id)
+
+	product := &models.Product{}
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&product.ID,
+		&product.Name,
+		&product.AssetType,
+		&product.Price,
+		&product.Tenor,
+		&product.Frequency,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.DeletedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("product with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) Delete(id int64) error {
+	ctx := context.Background()
+	query := "UPDATE products SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+
+	rowsAffected := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFoundf("product with id %d not found", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+type ReportRepository interface {
+	// GetPortfolioSnapshot returns outstanding principal and portfolio at risk
+	// as of the end of the given date, using the allocations made by then
+	GetPortfolioSnapshot(asOf time.Time) (*models.PortfolioSnapshot, error)
+	// GetPortfolioActivity sums deployments, expected and actual collections
+	// between from and to, both inclusive
+	GetPortfolioActivity(from, to time.Time) (*models.PortfolioActivity, error)
+}
+
+type reportRepository struct {
+	db DBTX
+}
+
+func NewReportRepository(db DBTX) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) GetPortfolioSnapshot(asOf time.Time) (*models.PortfolioSnapshot, error) {
+	ctx := context.Background()
+	query := `
+		WITH paid AS (
+			SELECT installment_id,
+				SUM(amount) AS total_paid,
+				SUM(amount) FILTER (WHERE component = 'PRINCIPAL') AS principal_paid
+			FROM payment_allocations
+			WHERE created_at < $1::date + 1
+			GROUP BY installment_id
+		),
+		installment_balances AS (
+			SELECT i.schedule_id, i.due_date,
+				i.amount_due - COALESCE(p.principal_paid, 0) AS principal_outstanding,
+				i.amount_due + i.fee_due + i.penalty_due - COALESCE(p.total_paid, 0) AS outstanding
+			FROM installments i
+			LEFT JOIN paid p ON p.installment_id = i.id
+		),
+		-- Deployments cancelled by then were recorded in error and are not owed
+		schedule_balances AS (
+			SELECT s.id,
+				SUM(b.principal_outstanding) AS principal_outstanding,
+				MIN(b.due_date) FILTER (WHERE b.outstanding > 0 AND b.due_date < $1::date) AS oldest_overdue
+			FROM repayment_schedules s
+			JOIN transactions t ON t.id = s.transaction_id
+			JOIN deployments d ON d.id = s.deployment_id
+			JOIN installment_balances b ON b.schedule_id = s.id
+			WHERE t.transaction_date < $1::date + 1
+				AND NOT (d.status = 'CANCELLED' AND COALESCE(d.closed_at, d.created_at) < $1::date + 1)
+			GROUP BY s.id
+		)
+		SELECT
+			COUNT(*) FILTER (WHERE principal_outstanding > 0),
+			COALESCE(SUM(principal_outstanding), 0),
+			COALESCE(SUM(principal_outstanding) FILTER (WHERE oldest_overdue < $1::date - 7), 0),
+			COALESCE(SUM(principal_outstanding) FILTER (WHERE oldest_overdue < $1::date - 30), 0),
+			COALESCE(SUM(principal_outstanding) FILTER (WHERE oldest_overdue < $1::date - 90), 0)
+		FROM schedule_balances
+	`
+
+	snapshot := &models.PortfolioSnapshot{AsOf: asOf}
+	err := r.db.QueryRow(ctx, query, asOf).Scan(
+		&snapshot.ActiveDeployments,
+		&snapshot.Outstanding,
+		&snapshot.PAR7.Amount,
+		&snapshot.PAR30.Amount,
+		&snapshot.PAR90.Amount,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio snapshot: %w", err)
+	}
+
+	snapshot.PAR7.Ratio = models.Percentage(snapshot.PAR7.Amount, snapshot.Outstanding)
+	snapshot.PAR30.Ratio = models.Percentage(snapshot.PAR30.Amount, snapshot.Outstanding)
+	snapshot.PAR90.Ratio = models.Percentage(snapshot.PAR90.Amount, snapshot.Outstanding)
+
+	return snapshot, nil
+}
+
+func (r *reportRepository) GetPortfolioActivity(from, to time.Time) (*models.PortfolioActivity, error) {
+	ctx := context.Background()
+
+	// Collections are completed payments less the reversals of payments in the range
+	query := `
+		-- Deployments cancelled by the end of the range were recorded in error
+		-- and neither disbursed nor owed
+		WITH deployed AS (
+			SELECT d.id, t.amount, t.transaction_date
+			FROM deployments d
+			JOIN transactions t ON t.id = d.transaction_id
+			WHERE NOT (d.status = 'CANCELLED' AND COALESCE(d.closed_at, d.created_at) < $2::date + 1)
+		)
+		SELECT
+			(SELECT COUNT(*) FROM deployed
+				WHERE transaction_date >= $1::date AND transaction_date < $2::date + 1),
+			(SELECT COALESCE(SUM(amount), 0) FROM deployed
+				WHERE transaction_date >= $1::date AND transaction_date < $2::date + 1),
+			(SELECT COALESCE(SUM(i.amount_due + i.fee_due + i.penalty_due), 0) FROM installments i
+				JOIN repayment_schedules s ON s.id = i.schedule_id
+				JOIN deployed d ON d.id = s.deployment_id
+				WHERE i.due_date >= $1::date AND i.due_date <= $2::date),
+			(SELECT COALESCE(SUM(amount), 0) FROM transactions
+				WHERE type = 'PAYMENT' AND status = 'COMPLETE' AND transaction_date >= $1::date AND transaction_date < $2::date + 1)
+			- (SELECT COALESCE(SUM(r.amount), 0) FROM transactions r
+				JOIN transactions o ON o.id = r.reversal_of
+				WHERE r.type = 'REVERSAL' AND o.type = 'PAYMENT' AND r.transaction_date >= $1::date AND r.transaction_date < $2::date + 1)
+	`
+
+	activity := &models.PortfolioActivity{From: from, To: to}
+	err := r.db.QueryRow(ctx, query, from, to).Scan(
+		&activity.DeploymentsCount,
+		&activity.DisbursedAmount,
+		&activity.ExpectedCollections,
+		&activity.Collections,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio activity: %w", err)
+	}
+
+	activity.CollectionRate = models.Percentage(activity.Collections, activity.ExpectedCollections)
+
+	return activity, nil
+}
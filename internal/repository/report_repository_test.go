@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+)
+
+// testTx migrates the database named by TEST_DATABASE_URL and returns a
+// transaction that is rolled back when the test ends. Tests that need it are
+// skipped when the variable is not set.
+func testTx(t *testing.T) pgx.Tx {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, os.DirFS("../../migrations"))
+	if err != nil {
+		t.Fatalf("failed to create goose provider: %v", err)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		pool.Close()
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		tx.Rollback(ctx)
+		pool.Close()
+	})
+	return tx
+}
+
+// seedDeployment records a deployment made on deployedOn with a single
+// installment of the full amount due on due. A non-nil closedAt closes it with
+// status.
+func seedDeployment(t *testing.T, tx pgx.Tx, customerID, accountID int64, reference string, amount models.Money, deployedOn, due time.Time, status models.DeploymentStatus, closedAt *time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	var transactionID, deploymentID, scheduleID int64
+	err := tx.QueryRow(ctx, `
+		INSERT INTO transactions (customer_id, account_id, reference, amount, status, type, direction, transaction_date)
+		VALUES ($1, $2, $3, $4, 'PENDING', 'DEPLOYMENT', 'DEBIT', $5)
+		RETURNING id
+	`, customerID, accountID, reference, amount, deployedOn).Scan(&transactionID)
+	if err == nil {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO deployments (customer_id, account_id, transaction_id, reference, amount, status, start_date, end_date, closed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id
+		`, customerID, accountID, transactionID, reference, amount, status, deployedOn, due, closedAt).Scan(&deploymentID)
+	}
+	if err == nil {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO repayment_schedules (transaction_id, deployment_id, customer_id, account_id, principal, tenor, frequency, start_date)
+			VALUES ($1, $2, $3, $4, $5, 1, 'MONTHLY', $6)
+			RETURNING id
+		`, transactionID, deploymentID, customerID, accountID, amount, deployedOn).Scan(&scheduleID)
+	}
+	if err == nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO installments (schedule_id, sequence, due_date, amount_due)
+			VALUES ($1, 1, $2, $3)
+		`, scheduleID, due, amount)
+	}
+	if err != nil {
+		t.Fatalf("failed to seed deployment %s: %v", reference, err)
+	}
+}
+
+func TestGetPortfolioActivity(t *testing.T) {
+	tx := testTx(t)
+	ctx := context.Background()
+
+	var customerID, accountID int64
+	err := tx.QueryRow(ctx, `
+		INSERT INTO customers (email, first_name, last_name) VALUES ('rider@example.com', 'Ada', 'Rider')
+		RETURNING id
+	`).Scan(&customerID)
+	if err == nil {
+		err = tx.QueryRow(ctx, `INSERT INTO accounts (customer_id) VALUES ($1) RETURNING id`, customerID).Scan(&accountID)
+	}
+	if err != nil {
+		t.Fatalf("failed to seed customer: %v", err)
+	}
+
+	// One live deployment and one reversed the day after it was recorded
+	cancelledAt := date(2025, 1, 7)
+	seedDeployment(t, tx, customerID, accountID, "DEP-LIVE", models.NewMoneyFromMajor(10000),
+		date(2025, 1, 5), date(2025, 1, 20), models.DeploymentStatusActive, nil)
+	seedDeployment(t, tx, customerID, accountID, "DEP-CANCELLED", models.NewMoneyFromMajor(20000),
+		date(2025, 1, 6), date(2025, 1, 20), models.DeploymentStatusCancelled, &cancelledAt)
+
+	tests := []struct {
+		name          string
+		from, to      time.Time
+		wantCount     int
+		wantDisbursed models.Money
+		wantExpected  models.Money
+	}{
+		{
+			name:          "cancelled deployment is left out",
+			from:          date(2025, 1, 1),
+			to:            date(2025, 1, 31),
+			wantCount:     1,
+			wantDisbursed: models.NewMoneyFromMajor(10000),
+			wantExpected:  models.NewMoneyFromMajor(10000),
+		},
+		{
+			name:          "deployment cancelled after the range still counts",
+			from:          date(2025, 1, 1),
+			to:            date(2025, 1, 6),
+			wantCount:     2,
+			wantDisbursed: models.NewMoneyFromMajor(30000),
+			wantExpected:  0,
+		},
+	}
+
+	r := NewReportRepository(tx)
+	for _, tt := range tests {
+		activity, err := r.GetPortfolioActivity(tt.from, tt.to)
+		if err != nil {
+			t.Errorf("%s: returned error: %v", tt.name, err)
+			continue
+		}
+		if activity.DeploymentsCount != tt.wantCount {
+			t.Errorf("%s: deployments count = %d, want %d", tt.name, activity.DeploymentsCount, tt.wantCount)
+		}
+		if activity.DisbursedAmount != tt.wantDisbursed {
+			t.Errorf("%s: disbursed amount = %s, want %s", tt.name, activity.DisbursedAmount, tt.wantDisbursed)
+		}
+		if activity.ExpectedCollections != tt.wantExpected {
+			t.Errorf("%s: expected collections = %s, want %s", tt.name, activity.ExpectedCollections, tt.wantExpected)
+		}
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
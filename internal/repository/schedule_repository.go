@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type ScheduleRepository interface {
+	// Create stores the schedule together with its installments
+	Create(schedule *models.RepaymentSchedule) (*models.RepaymentSchedule, error)
+	GetByID(id int64) (*models.RepaymentSchedule, error)
+	GetByTransactionID(transactionID int64) (*models.RepaymentSchedule, error)
+	GetByDeploymentID(deploymentID int64) (*models.RepaymentSchedule, error)
+	GetByCustomerID(customerID int64) ([]*models.RepaymentSchedule, error)
+	// GetExposure counts the customer's active schedules and sums what is
+	// still owed on them
+	GetExposure(customerID int64) (*models.Exposure, error)
+	// LockOpenInstallments locks the unpaid installments of the account's active
+	// schedules, ordered by due date. It must run inside a unit of work.
+	LockOpenInstallments(accountID int64) ([]*models.Installment, error)
+	// LockInstallment locks a single installment. It must run inside a unit of work.
+	LockInstallment(id int64) (*models.Installment, error)
+	// AddInstallmentPenalty adds amount to the penalties due on the installment
+	AddInstallmentPenalty(id int64, amount models.Money) error
+	// UpdateInstallmentPayment stores the paid amounts, status and paid_at of an installment
+	UpdateInstallmentPayment(installment *models.Installment) error
+	// SettleIfPaid marks the schedule as SETTLED once every installment is paid
+	// and reports whether it did
+	SettleIfPaid(scheduleID int64) (bool, error)
+	// Reopen moves a SETTLED schedule back to ACTIVE and reports whether it did
+	Reopen(scheduleID int64) (bool, error)
+	// Cancel marks the deployment's active schedule as CANCELLED so its
+	// installments are no longer due
+	Cancel(deploymentID int64) error
+}
+
+type scheduleRepository struct {
+	db DBTX
+}
+
+func NewScheduleRepository(db DBTX) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+const scheduleColumns = `id, deployment_id, transaction_id, customer_id, account_id, product_id, principal, tenor, frequency, start_date, status, created_at, updated_at`
+
+const installmentColumns = `id, schedule_id, sequence, due_date, amount_due, amount_paid, fee_due, fee_paid, penalty_due, penalty_paid, status, paid_at, created_at, updated_at`
+
+func scanSchedule(row pgx.Row) (*models.RepaymentSchedule, error) {
+	schedule := &models.RepaymentSchedule{}
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.DeploymentID,
+		&schedule.TransactionID,
+		&schedule.CustomerID,
+		&schedule.AccountID,
+		&schedule.ProductID,
+		&schedule.Principal,
+		&schedule.Tenor,
+		&schedule.Frequency,
+		&schedule.StartDate,
+		&schedule.Status,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	return schedule, err
+}
+
+func scanInstallment(row pgx.Row) (*models.Installment, error) {
+	installment := &models.Installment{}
+	err := row.Scan(
+		&installment.ID,
+		&installment.ScheduleID,
+		&installment.Sequence,
+		&installment.DueDate,
+		&installment.AmountDue,
+		&installment.AmountPaid,
+		&installment.FeeDue,
+		&installment.FeePaid,
+		&installment.PenaltyDue,
+		&installment.PenaltyPaid,
+		&installment.Status,
+		&installment.PaidAt,
+		&installment.CreatedAt,
+		&installment.UpdatedAt,
+	)
+	return installment, err
+}
+
+func (r *scheduleRepository) Create(scheduleReq *models.RepaymentSchedule) (*models.RepaymentSchedule, error) {
+	ctx := context.Background()
+
+	// Start a transaction so the schedule is never stored without its installments
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO repayment_schedules (deployment_id, transaction_id, customer_id, account_id, product_id, principal, tenor, frequency, start_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING ` + scheduleColumns
+
+	schedule, err := scanSchedule(tx.QueryRow(
+		ctx,
+		query,
+		scheduleReq.DeploymentID,
+		scheduleReq.TransactionID,
+		scheduleReq.CustomerID,
+		scheduleReq.AccountID,
+		scheduleReq.ProductID,
+		scheduleReq.Principal,
+		scheduleReq.Tenor,
+		scheduleReq.Frequency,
+		scheduleReq.StartDate,
+		models.ScheduleStatusActive,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repayment schedule: %w", err)
+	}
+
+	installmentQuery := `
+		INSERT INTO installments (schedule_id, sequence, due_date, amount_due, amount_paid, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 0, $5, NOW(), NOW())
+		RETURNING ` + installmentColumns
+
+	schedule.Installments = make([]*models.Installment, 0, len(scheduleReq.Installments))
+	for _, installmentReq := range scheduleReq.Installments {
+		installment, err := scanInstallment(tx.QueryRow(
+			ctx,
+			installmentQuery,
+			schedule.ID,
+			installmentReq.Sequence,
+			installmentReq.DueDate,
+			installmentReq.AmountDue,
+			models.InstallmentStatusPending,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create installment: %w", err)
+		}
+		schedule.Installments = append(schedule.Installments, installment)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (r *scheduleRepository) GetByID(id int64) (*models.RepaymentSchedule, error) {
+	ctx := context.Background()
+	query := `SELECT ` + scheduleColumns + ` FROM repayment_schedules WHERE id = $1`
+
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("repayment schedule with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repayment schedule: %w", err)
+	}
+
+	return r.withInstallments(ctx, schedule)
+}
+
+func (r *scheduleRepository) GetByTransactionID(transactionID int64) (*models.RepaymentSchedule, error) {
+	ctx := context.Background()
+	query := `SELECT ` + scheduleColumns + ` FROM repayment_schedules WHERE transaction_id = $1`
+
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, query, transactionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("repayment schedule for transaction %d not found", transactionID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repayment schedule: %w", err)
+	}
+
+	return r.withInstallments(ctx, schedule)
+}
+
+func (r *scheduleRepository) GetByDeploymentID(deploymentID int64) (*models.RepaymentSchedule, error) {
+	ctx := context.Background()
+	query := `SELECT ` + scheduleColumns + ` FROM repayment_schedules WHERE deployment_id = $1`
+
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, query, deploymentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("repayment schedule for deployment %d not found", deploymentID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repayment schedule: %w", err)
+	}
+
+	return r.withInstallments(ctx, schedule)
+}
+
+func (r *scheduleRepository) GetByCustomerID(customerID int64) ([]*models.RepaymentSchedule, error) {
+	ctx := context.Background()
+	query := `SELECT ` + scheduleColumns + ` FROM repayment_schedules WHERE customer_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repayment schedules: %w", err)
+	}
+
+	schedules := []*models.RepaymentSchedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan repayment schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating repayment schedules: %w", err)
+	}
+
+	// Installments are loaded after the rows are closed since a pgx.Tx
+	// cannot run a second query while the first is still being read
+	for _, schedule := range schedules {
+		if _, err := r.withInstallments(ctx, schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	return schedules, nil
+}
+
+func (r *scheduleRepository) GetExposure(customerID int64) (*models.Exposure, error) {
+	ctx := context.Background()
+	query := `
+		SELECT
+			COUNT(DISTINCT s.id),
+			COALESCE(SUM(i.amount_due - i.amount_paid + i.fee_due - i.fee_paid + i.penalty_due - i.penalty_paid), 0)
+		FROM repayment_schedules s
+		JOIN installments i ON i.schedule_id = s.id
+		WHERE s.customer_id = $1 AND s.status = $2
+	`
+
+	exposure := &models.Exposure{}
+	err := r.db.QueryRow(ctx, query, customerID, models.ScheduleStatusActive).Scan(&exposure.ActiveDeployments, &exposure.Outstanding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exposure: %w", err)
+	}
+
+	return exposure, nil
+}
+
+func (r *scheduleRepository) withInstallments(ctx context.Context, schedule *models.RepaymentSchedule) (*models.RepaymentSchedule, error) {
+	query := `SELECT ` + installmentColumns + ` FROM installments WHERE schedule_id = $1 ORDER BY sequence ASC`
+
+	rows, err := r.db.Query(ctx, query, schedule.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installments: %w", err)
+	}
+	defer rows.Close()
+
+	schedule.Installments = []*models.Installment{}
+	for rows.Next() {
+		installment, err := scanInstallment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan installment: %w", err)
+		}
+		schedule.Installments = append(schedule.Installments, installment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating installments: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (r *scheduleRepository) LockOpenInstallments(accountID int64) ([]*models.Installment, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + prefixColumns("i", installmentColumns) + `
+		FROM installments i
+		JOIN repayment_schedules s ON s.id = i.schedule_id
+		WHERE s.account_id = $1 AND s.status = $2 AND i.status <> $3
+		ORDER BY i.due_date ASC, i.schedule_id ASC, i.sequence ASC
+		FOR UPDATE OF i
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID, models.ScheduleStatusActive, models.InstallmentStatusPaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock open installments: %w", err)
+	}
+	defer rows.Close()
+
+	installments := []*models.Installment{}
+	for rows.Next() {
+		installment, err := scanInstallment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan installment: %w", err)
+		}
+		installments = append(installments, installment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating installments: %w", err)
+	}
+
+	return installments, nil
+}
+
+func (r *scheduleRepository) LockInstallment(id int64) (*models.Installment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + installmentColumns + ` FROM installments WHERE id = $1 FOR UPDATE`
+
+	installment, err := scanInstallment(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("installment with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock installment: %w", err)
+	}
+
+	return installment, nil
+}
+
+func (r *scheduleRepository) AddInstallmentPenalty(id int64, amount models.Money) error {
+	ctx := context.Background()
+	query := `UPDATE installments SET penalty_due = penalty_due + $1, updated_at = NOW() WHERE id = $2`
+
+	result, err := r.db.Exec(ctx, query, amount, id)
+	if err != nil {
+		return fmt.Errorf("failed to add installment penalty: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notFoundf("installment with id %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *scheduleRepository) UpdateInstallmentPayment(installment *models.Installment) error {
+	ctx := context.Background()
+	query := `
+		UPDATE installments
+		SET amount_paid = $1, fee_paid = $2, penalty_paid = $3, status = $4, paid_at = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		installment.AmountPaid,
+		installment.FeePaid,
+		installment.PenaltyPaid,
+		installment.Status,
+		installment.PaidAt,
+		installment.ID,
+	).Scan(&installment.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFoundf("installment with id %d not found", installment.ID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update installment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scheduleRepository) SettleIfPaid(scheduleID int64) (bool, error) {
+	ctx := context.Background()
+	query := `
+		UPDATE repayment_schedules
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+			AND status = $3
+			AND NOT EXISTS (SELECT 1 FROM installments WHERE schedule_id = $2 AND status <> $4)
+	`
+
+	result, err := r.db.Exec(ctx, query, models.ScheduleStatusSettled, scheduleID, models.ScheduleStatusActive, models.InstallmentStatusPaid)
+	if err != nil {
+		return false, fmt.Errorf("failed to settle repayment schedule: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *scheduleRepository) Reopen(scheduleID int64) (bool, error) {
+	ctx := context.Background()
+	query := `UPDATE repayment_schedules SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3`
+
+	result, err := r.db.Exec(ctx, query, models.ScheduleStatusActive, scheduleID, models.ScheduleStatusSettled)
+	if err != nil {
+		return false, fmt.Errorf("failed to reopen repayment schedule: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *scheduleRepository) Cancel(deploymentID int64) error {
+	ctx := context.Background()
+	query := `UPDATE repayment_schedules SET status = $1, updated_at = NOW() WHERE deployment_id = $2 AND status = $3`
+
+	if _, err := r.db.Exec(ctx, query, models.ScheduleStatusCancelled, deploymentID, models.ScheduleStatusActive); err != nil {
+		return fmt.Errorf("failed to cancel repayment schedule: %w", err)
+	}
+
+	return nil
+}
+
+// prefixColumns qualifies each column in a comma separated list with a table alias
+func prefixColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, column := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type SuspenseRepository interface {
+	// Create stores the payment unless one with the same reference already
+	// exists. It returns the stored payment and whether this call inserted it.
+	Create(payment *models.SuspensePayment) (*models.SuspensePayment, bool, error)
+	// GetByID returns the payment with its events
+	GetByID(id int64) (*models.SuspensePayment, error)
+	// GetAll lists payments, newest first, optionally only those with status
+	GetAll(status *models.SuspenseStatus) ([]*models.SuspensePayment, error)
+	// LockByID selects the payment FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.SuspensePayment, error)
+	// Resolve stores the payment's status, customer_id, notification_id,
+	// resolved_by and note and sets resolved_at
+	Resolve(payment *models.SuspensePayment) error
+	CreateEvent(event *models.SuspenseEvent) (*models.SuspenseEvent, error)
+}
+
+type suspenseRepository struct {
+	db DBTX
+}
+
+func NewSuspenseRepository(db DBTX) SuspenseRepository {
+	return &suspenseRepository{db: db}
+}
+
+const suspensePaymentColumns = `id, provider, reference, customer_ref, amount, transaction_date, reason, status, customer_id, notification_id, resolved_by, resolved_at, note, created_at, updated_at`
+
+const suspenseEventColumns = `id, suspense_id, action, actor, customer_id, note, created_at`
+
+func scanSuspensePayment(row pgx.Row) (*models.SuspensePayment, error) {
+	payment := &models.SuspensePayment{}
+	err := row.Scan(
+		&payment.ID,
+		&payment.Provider,
+		&payment.Reference,
+		&payment.CustomerRef,
+		&payment.Amount,
+		&payment.TransactionDate,
+		&payment.Reason,
+		&payment.Status,
+		&payment.CustomerID,
+		&payment.NotificationID,
+		&payment.ResolvedBy,
+		&payment.ResolvedAt,
+		&payment.Note,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+	return payment, err
+}
+
+func scanSuspenseEvent(row pgx.Row) (*models.SuspenseEvent, error) {
+	event := &models.SuspenseEvent{}
+	err := row.Scan(
+		&event.ID,
+		&event.SuspenseID,
+		&event.Action,
+		&event.Actor,
+		&event.CustomerID,
+		&event.Note,
+		&event.CreatedAt,
+	)
+	return event, err
+}
+
+func (r *suspenseRepository) Create(paymentReq *models.SuspensePayment) (*models.SuspensePayment, bool, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO suspense_payments (provider, reference, customer_ref, amount, transaction_date, reason, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (provider, reference) DO NOTHING
+		RETURNING ` + suspensePaymentColumns
+
+	payment, err := scanSuspensePayment(r.db.QueryRow(
+		ctx,
+		query,
+		paymentReq.Provider,
+		paymentReq.Reference,
+		paymentReq.CustomerRef,
+		paymentReq.Amount,
+		paymentReq.TransactionDate,
+		paymentReq.Reason,
+		models.SuspenseStatusUnmatched,
+	))
+
+	// Nothing was inserted, so the payment has been seen before
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, err := scanSuspensePayment(r.db.QueryRow(ctx, `SELECT `+suspensePaymentColumns+` FROM suspense_payments WHERE provider = $1 AND reference = $2`, paymentReq.Provider, paymentReq.Reference))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get suspense payment: %w", err)
+		}
+		return existing, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create suspense payment: %w", err)
+	}
+
+	return payment, true, nil
+}
+
+func (r *suspenseRepository) GetByID(id int64) (*models.SuspensePayment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + suspensePaymentColumns + ` FROM suspense_payments WHERE id = $1`
+
+	payment, err := scanSuspensePayment(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("suspense payment with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspense payment: %w", err)
+	}
+
+	return r.withEvents(ctx, payment)
+}
+
+func (r *suspenseRepository) GetAll(status *models.SuspenseStatus) ([]*models.SuspensePayment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + suspensePaymentColumns + ` FROM suspense_payments`
+	args := []interface{}{}
+
+	if status != nil {
+		query += ` WHERE status = $1`
+		args = append(args, *status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspense payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []*models.SuspensePayment{}
+	for rows.Next() {
+		payment, err := scanSuspensePayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan suspense payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suspense payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+func (r *suspenseRepository) LockByID(id int64) (*models.SuspensePayment, error) {
+	ctx := context.Background()
+	query := `SELECT ` + suspensePaymentColumns + ` FROM suspense_payments WHERE id = $1 FOR UPDATE`
+
+	payment, err := scanSuspensePayment(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("suspense payment with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock suspense payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+func (r *suspenseRepository) Resolve(payment *models.SuspensePayment) error {
+	ctx := context.Background()
+	query := `
+		UPDATE suspense_payments
+		SET status = $1, customer_id = $2, notification_id = $3, resolved_by = $4, note = $5, resolved_at = NOW(), updated_at = NOW()
+		WHERE id = $6
+		RETURNING resolved_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		payment.Status,
+		payment.CustomerID,
+		payment.NotificationID,
+		payment.ResolvedBy,
+		payment.Note,
+		payment.ID,
+	).Scan(&payment.ResolvedAt, &payment.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFoundf("suspense payment with id %d not found", payment.ID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to resolve suspense payment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *suspenseRepository) CreateEvent(eventReq *models.SuspenseEvent) (*models.SuspenseEvent, error) {
+	ctx := context.Background()
+	query := `
+		INSERT INTO suspense_payment_events (suspense_id, action, actor, customer_id, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING ` + suspenseEventColumns
+
+	event, err := scanSuspenseEvent(r.db.QueryRow(
+		ctx,
+		query,
+		eventReq.SuspenseID,
+		eventReq.Action,
+		eventReq.Actor,
+		eventReq.CustomerID,
+		eventReq.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create suspense event: %w", err)
+	}
+
+	return event, nil
+}
+
+func (r *suspenseRepository) withEvents(ctx context.Context, payment *models.SuspensePayment) (*models.SuspensePayment, error) {
+	query := `SELECT ` + suspenseEventColumns + ` FROM suspense_payment_events WHERE suspense_id = $1 ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(ctx, query, payment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspense events: %w", err)
+	}
+	defer rows.Close()
+
+	payment.Events = []*models.SuspenseEvent{}
+	for rows.Next() {
+		event, err := scanSuspenseEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan suspense event: %w", err)
+		}
+		payment.Events = append(payment.Events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating suspense events: %w", err)
+	}
+
+	return payment, nil
+}
@@ -4,29 +4,50 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type TransactionRepository interface {
 	Create(transaction *models.CreateTransactionRequest) (*models.Transaction, error)
 	GetByID(id int64) (*models.Transaction, error)
+	// GetByReference returns the PAYMENT transaction with the provider
+	// reference. Other transaction types may reuse a reference and are ignored.
 	GetByReference(reference string) (*models.Transaction, error)
+	// LockByReference selects the PAYMENT transaction FOR UPDATE and returns nil
+	// if none exists; it must run inside a unit of work
+	LockByReference(reference string) (*models.Transaction, error)
+	// LockByID selects the transaction FOR UPDATE; it must run inside a unit of work
+	LockByID(id int64) (*models.Transaction, error)
+	GetReversalOf(transactionID int64) (*models.Transaction, error)
 	GetByCustomerID(customerID int64) ([]*models.Transaction, error)
+	FindByCustomerID(customerID int64, filter *models.TransactionFilter) ([]*models.Transaction, error)
 	GetByAccountID(accountID int64) ([]*models.Transaction, error)
 	GetByCustomerAndAccountID(customerID, accountID int64) ([]*models.Transaction, error)
 	GetAll() ([]*models.Transaction, error)
 	Update(id int64, transaction *models.UpdateTransactionRequest) (*models.Transaction, error)
 	Delete(id int64) error
+	// GetBalanceAsOf sums the posted transactions of the account dated at or
+	// before asOf, so back-dated transactions count from their transaction_date
+	GetBalanceAsOf(accountID int64, asOf time.Time) (models.Money, error)
+	// GetPostedBalance sums all posted transactions of the account, which is
+	// what the account balance should be
+	GetPostedBalance(accountID int64) (models.Money, error)
+	// GetPostedByAccountID returns the posted transactions of the account dated
+	// before before, oldest first
+	GetPostedByAccountID(accountID int64, before time.Time) ([]*models.Transaction, error)
+	// GetBalanceHistory returns the closing balance on every date from from to
+	// to, stepping by interval
+	GetBalanceHistory(accountID int64, from, to time.Time, interval models.BalanceInterval) ([]*models.BalancePoint, error)
 }
 
 type transactionRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
-func NewTransactionRepository(db *pgxpool.Pool) TransactionRepository {
+func NewTransactionRepository(db DBTX) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
@@ -45,32 +66,38 @@ func (r *transactionRepository) Create(transactionReq *models.CreateTransactionR
 	// If transaction_date is provided, include it; otherwise use database default (NOW())
 	if transactionReq.TransactionDate != nil {
 		query = `
-			INSERT INTO transactions (customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
-			RETURNING id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+			INSERT INTO transactions (customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+			RETURNING id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		`
 		args = []interface{}{
 			transactionReq.CustomerID,
 			transactionReq.AccountID,
 			transactionReq.Reference,
+			transactionReq.Type,
+			transactionReq.Direction,
 			transactionReq.Amount,
 			transactionReq.Status,
 			description,
+			transactionReq.ReversalOf,
 			*transactionReq.TransactionDate,
 		}
 	} else {
 		query = `
-			INSERT INTO transactions (customer_id, account_id, reference, amount, status, description, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-			RETURNING id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+			INSERT INTO transactions (customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+			RETURNING id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		`
 		args = []interface{}{
 			transactionReq.CustomerID,
 			transactionReq.AccountID,
 			transactionReq.Reference,
+			transactionReq.Type,
+			transactionReq.Direction,
 			transactionReq.Amount,
 			transactionReq.Status,
 			description,
+			transactionReq.ReversalOf,
 		}
 	}
 
@@ -80,9 +107,12 @@ func (r *transactionRepository) Create(transactionReq *models.CreateTransactionR
 		&transaction.CustomerID,
 		&transaction.AccountID,
 		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
 		&transaction.Amount,
 		&transaction.Status,
 		&transaction.Description,
+		&transaction.ReversalOf,
 		&transaction.TransactionDate,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
@@ -98,7 +128,7 @@ func (r *transactionRepository) Create(transactionReq *models.CreateTransactionR
 func (r *transactionRepository) GetByID(id int64) (*models.Transaction, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		FROM transactions
 		WHERE id = $1
 	`
@@ -109,16 +139,19 @@ func (r *transactionRepository) GetByID(id int64) (*models.Transaction, error) {
 		&transaction.CustomerID,
 		&transaction.AccountID,
 		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
 		&transaction.Amount,
 		&transaction.Status,
 		&transaction.Description,
+		&transaction.ReversalOf,
 		&transaction.TransactionDate,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("transaction with id %d not found", id)
+		return nil, notFoundf("transaction with id %d not found", id)
 	}
 
 	if err != nil {
@@ -131,9 +164,9 @@ func (r *transactionRepository) GetByID(id int64) (*models.Transaction, error) {
 func (r *transactionRepository) GetByReference(reference string) (*models.Transaction, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		FROM transactions
-		WHERE reference = $1
+		WHERE reference = $1 AND type = 'PAYMENT'
 	`
 
 	transaction := &models.Transaction{}
@@ -142,16 +175,129 @@ func (r *transactionRepository) GetByReference(reference string) (*models.Transa
 		&transaction.CustomerID,
 		&transaction.AccountID,
 		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
 		&transaction.Amount,
 		&transaction.Status,
 		&transaction.Description,
+		&transaction.ReversalOf,
 		&transaction.TransactionDate,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("transaction with reference %s not found", reference)
+		return nil, notFoundf("payment with reference %s not found", reference)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionRepository) LockByReference(reference string) (*models.Transaction, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
+		FROM transactions
+		WHERE reference = $1 AND type = 'PAYMENT'
+		FOR UPDATE
+	`
+
+	transaction := &models.Transaction{}
+	err := r.db.QueryRow(ctx, query, reference).Scan(
+		&transaction.ID,
+		&transaction.CustomerID,
+		&transaction.AccountID,
+		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
+		&transaction.Amount,
+		&transaction.Status,
+		&transaction.Description,
+		&transaction.ReversalOf,
+		&transaction.TransactionDate,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionRepository) LockByID(id int64) (*models.Transaction, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	transaction := &models.Transaction{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&transaction.ID,
+		&transaction.CustomerID,
+		&transaction.AccountID,
+		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
+		&transaction.Amount,
+		&transaction.Status,
+		&transaction.Description,
+		&transaction.ReversalOf,
+		&transaction.TransactionDate,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("transaction with id %d not found", id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionRepository) GetReversalOf(transactionID int64) (*models.Transaction, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
+		FROM transactions
+		WHERE reversal_of = $1
+	`
+
+	transaction := &models.Transaction{}
+	err := r.db.QueryRow(ctx, query, transactionID).Scan(
+		&transaction.ID,
+		&transaction.CustomerID,
+		&transaction.AccountID,
+		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
+		&transaction.Amount,
+		&transaction.Status,
+		&transaction.Description,
+		&transaction.ReversalOf,
+		&transaction.TransactionDate,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, notFoundf("reversal of transaction with id %d not found", transactionID)
 	}
 
 	if err != nil {
@@ -164,7 +310,7 @@ func (r *transactionRepository) GetByReference(reference string) (*models.Transa
 func (r *transactionRepository) GetByCustomerID(customerID int64) ([]*models.Transaction, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		FROM transactions
 		WHERE customer_id = $1
 		ORDER BY transaction_date DESC
@@ -184,9 +330,73 @@ func (r *transactionRepository) GetByCustomerID(customerID int64) ([]*models.Tra
 			&transaction.CustomerID,
 			&transaction.AccountID,
 			&transaction.Reference,
+			&transaction.Type,
+			&transaction.Direction,
 			&transaction.Amount,
 			&transaction.Status,
 			&transaction.Description,
+			&transaction.ReversalOf,
+			&transaction.TransactionDate,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) FindByCustomerID(customerID int64, filter *models.TransactionFilter) ([]*models.Transaction, error) {
+	ctx := context.Background()
+	// Build dynamic filter query
+	query := `
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
+		FROM transactions
+		WHERE customer_id = $1`
+	args := []interface{}{customerID}
+	argPos := 2
+
+	if filter != nil && filter.Type != nil {
+		query += fmt.Sprintf(" AND type = $%d", argPos)
+		args = append(args, *filter.Type)
+		argPos++
+	}
+
+	if filter != nil && filter.Direction != nil {
+		query += fmt.Sprintf(" AND direction = $%d", argPos)
+		args = append(args, *filter.Direction)
+		argPos++
+	}
+
+	query += " ORDER BY transaction_date DESC"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []*models.Transaction{}
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.CustomerID,
+			&transaction.AccountID,
+			&transaction.Reference,
+			&transaction.Type,
+			&transaction.Direction,
+			&transaction.Amount,
+			&transaction.Status,
+			&transaction.Description,
+			&transaction.ReversalOf,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
@@ -207,7 +417,7 @@ func (r *transactionRepository) GetByCustomerID(customerID int64) ([]*models.Tra
 func (r *transactionRepository) GetByAccountID(accountID int64) ([]*models.Transaction, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		FROM transactions
 		WHERE account_id = $1
 		ORDER BY created_at DESC
@@ -227,9 +437,12 @@ func (r *transactionRepository) GetByAccountID(accountID int64) ([]*models.Trans
 			&transaction.CustomerID,
 			&transaction.AccountID,
 			&transaction.Reference,
+			&transaction.Type,
+			&transaction.Direction,
 			&transaction.Amount,
 			&transaction.Status,
 			&transaction.Description,
+			&transaction.ReversalOf,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
@@ -250,7 +463,7 @@ func (r *transactionRepository) GetByAccountID(accountID int64) ([]*models.Trans
 func (r *transactionRepository) GetByCustomerAndAccountID(customerID, accountID int64) ([]*models.Transaction, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		FROM transactions
 		WHERE customer_id = $1 AND account_id = $2
 		ORDER BY created_at DESC
@@ -270,9 +483,12 @@ func (r *transactionRepository) GetByCustomerAndAccountID(customerID, accountID
 			&transaction.CustomerID,
 			&transaction.AccountID,
 			&transaction.Reference,
+			&transaction.Type,
+			&transaction.Direction,
 			&transaction.Amount,
 			&transaction.Status,
 			&transaction.Description,
+			&transaction.ReversalOf,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
@@ -293,7 +509,7 @@ func (r *transactionRepository) GetByCustomerAndAccountID(customerID, accountID
 func (r *transactionRepository) GetAll() ([]*models.Transaction, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
 		FROM transactions
 		ORDER BY created_at DESC
 	`
@@ -312,9 +528,12 @@ func (r *transactionRepository) GetAll() ([]*models.Transaction, error) {
 			&transaction.CustomerID,
 			&transaction.AccountID,
 			&transaction.Reference,
+			&transaction.Type,
+			&transaction.Direction,
 			&transaction.Amount,
 			&transaction.Status,
 			&transaction.Description,
+			&transaction.ReversalOf,
 			&transaction.TransactionDate,
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
@@ -357,7 +576,7 @@ func (r *transactionRepository) Update(id int64, transactionReq *models.UpdateTr
 		argPos++
 	}
 
-	query += fmt.Sprintf(" WHERE id = $%d RETURNING id, customer_id, account_id, reference, amount, status, description, transaction_date, created_at, updated_at", argPos)
+	query += fmt.Sprintf(" WHERE id = $%d RETURNING id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at", argPos)
 	args = append(args, id)
 
 	transaction := &models.Transaction{}
@@ -366,16 +585,19 @@ func (r *transactionRepository) Update(id int64, transactionReq *models.UpdateTr
 		&transaction.CustomerID,
 		&transaction.AccountID,
 		&transaction.Reference,
+		&transaction.Type,
+		&transaction.Direction,
 		&transaction.Amount,
 		&transaction.Status,
 		&transaction.Description,
+		&transaction.ReversalOf,
 		&transaction.TransactionDate,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("transaction with id %d not found", id)
+		return nil, notFoundf("transaction with id %d not found", id)
 	}
 
 	if err != nil {
@@ -396,8 +618,145 @@ func (r *transactionRepository) Delete(id int64) error {
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("transaction with id %d not found", id)
+		return notFoundf("transaction with id %d not found", id)
 	}
 
 	return nil
 }
+
+// postedCondition matches the transactions that make up an account balance:
+// completed ones, and deployments, which debit the account while PENDING. It is
+// the only definition of a posted transaction; statements filter with it too.
+// ADJUSTMENT transactions only bring a drifted balance back in line with the
+// other transactions, so they are not part of the history themselves.
+const postedCondition = `((status = 'COMPLETE' OR type = 'DEPLOYMENT') AND type <> 'ADJUSTMENT')`
+
+// signedAmount is the transaction amount signed by direction, credits positive
+const signedAmount = `CASE direction WHEN 'CREDIT' THEN amount ELSE -amount END`
+
+func (r *transactionRepository) GetBalanceAsOf(accountID int64, asOf time.Time) (models.Money, error) {
+	ctx := context.Background()
+	query := `
+		SELECT COALESCE(SUM(` + signedAmount + `), 0)
+		FROM transactions
+		WHERE account_id = $1 AND ` + postedCondition + ` AND transaction_date <= $2
+	`
+
+	var balance models.Money
+	if err := r.db.QueryRow(ctx, query, accountID, asOf).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+func (r *transactionRepository) GetPostedBalance(accountID int64) (models.Money, error) {
+	ctx := context.Background()
+	query := `
+		SELECT COALESCE(SUM(` + signedAmount + `), 0)
+		FROM transactions
+		WHERE account_id = $1 AND ` + postedCondition + `
+	`
+
+	var balance models.Money
+	if err := r.db.QueryRow(ctx, query, accountID).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("failed to get posted balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+func (r *transactionRepository) GetPostedByAccountID(accountID int64, before time.Time) ([]*models.Transaction, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, customer_id, account_id, reference, type, direction, amount, status, description, reversal_of, transaction_date, created_at, updated_at
+		FROM transactions
+		WHERE account_id = $1 AND ` + postedCondition + ` AND transaction_date < $2
+		ORDER BY transaction_date ASC, id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posted transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []*models.Transaction{}
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.CustomerID,
+			&transaction.AccountID,
+			&transaction.Reference,
+			&transaction.Type,
+			&transaction.Direction,
+			&transaction.Amount,
+			&transaction.Status,
+			&transaction.Description,
+			&transaction.ReversalOf,
+			&transaction.TransactionDate,
+			&transaction.CreatedAt,
+			&transaction.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+func (r *transactionRepository) GetBalanceHistory(accountID int64, from, to time.Time, interval models.BalanceInterval) ([]*models.BalancePoint, error) {
+	ctx := context.Background()
+
+	// Opening balance before the first date, then the running total of the
+	// daily movements, sampled at every point of the series
+	query := `
+		WITH opening AS (
+			SELECT COALESCE(SUM(` + signedAmount + `), 0) AS balance
+			FROM transactions
+			WHERE account_id = $1 AND ` + postedCondition + ` AND transaction_date < $2::date
+		),
+		movements AS (
+			SELECT transaction_date::date AS day, SUM(` + signedAmount + `) AS amount
+			FROM transactions
+			WHERE account_id = $1 AND ` + postedCondition + `
+				AND transaction_date >= $2::date AND transaction_date < $3::date + 1
+			GROUP BY transaction_date::date
+		),
+		points AS (
+			SELECT generate_series($2::date, $3::date, ('1 ' || $4)::interval)::date AS day
+		)
+		SELECT p.day,
+			(SELECT balance FROM opening) + COALESCE((SELECT SUM(m.amount) FROM movements m WHERE m.day <= p.day), 0)
+		FROM points p
+		ORDER BY p.day ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID, from, to, string(interval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance history: %w", err)
+	}
+	defer rows.Close()
+
+	points := []*models.BalancePoint{}
+	for rows.Next() {
+		point := &models.BalancePoint{}
+		if err := rows.Scan(&point.Date, &point.Balance); err != nil {
+			return nil, fmt.Errorf("failed to scan balance point: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating balance history: %w", err)
+	}
+
+	return points, nil
+}
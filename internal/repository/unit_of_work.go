@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repositories groups the repositories bound to a single database transaction
+type Repositories struct {
+	Customers    CustomerRepository
+	Accounts     AccountRepository
+	Transactions TransactionRepository
+	Ledger       LedgerRepository
+	Payments     PaymentNotificationRepository
+	Jobs         JobRepository
+	Schedules    ScheduleRepository
+	Allocations  AllocationRepository
+	Penalties    PenaltyRepository
+	Suspense     SuspenseRepository
+	Arrears      ArrearsRepository
+	Deployments  DeploymentRepository
+	Assets       AssetRepository
+	Guarantors   GuarantorRepository
+}
+
+func newRepositories(db DBTX) *Repositories {
+	return &Repositories{
+		Customers:    NewCustomerRepository(db),
+		Accounts:     NewAccountRepository(db),
+		Transactions: NewTransactionRepository(db),
+		Ledger:       NewLedgerRepository(db),
+		Payments:     NewPaymentNotificationRepository(db),
+		Jobs:         NewJobRepository(db),
+		Schedules:    NewScheduleRepository(db),
+		Allocations:  NewAllocationRepository(db),
+		Penalties:    NewPenaltyRepository(db),
+		Suspense:     NewSuspenseRepository(db),
+		Arrears:      NewArrearsRepository(db),
+		Deployments:  NewDeploymentRepository(db),
+		Assets:       NewAssetRepository(db),
+		Guarantors:   NewGuarantorRepository(db),
+	}
+}
+
+// UnitOfWork runs several repository calls in one pgx transaction
+type UnitOfWork interface {
+	// Do calls fn with repositories bound to a new transaction. The transaction
+	// is committed if fn returns nil and rolled back otherwise.
+	Do(fn func(repos *Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *pgxpool.Pool
+}
+
+func NewUnitOfWork(db *pgxpool.Pool) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(fn func(repos *Repositories) error) error {
+	ctx := context.Background()
+
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(newRepositories(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -15,6 +15,17 @@ func NewRouter(
 	deploymentService service.DeploymentService,
 	transactionService service.TransactionService,
 	accountService service.AccountService,
+	productService service.ProductService,
+	arrearsService service.ArrearsService,
+	reportService service.ReportService,
+	penaltyService service.PenaltyService,
+	statementService service.StatementService,
+	reconciliationService service.ReconciliationService,
+	suspenseService service.SuspenseService,
+	assetService service.AssetService,
+	guarantorService service.GuarantorService,
+	webhookVerifier *middleware.WebhookVerifier,
+	adminAuth *middleware.AdminAuth,
 ) *mux.Router {
 	router := mux.NewRouter()
 
@@ -23,6 +34,15 @@ func NewRouter(
 	deploymentHandler := handler.NewDeploymentHandler(deploymentService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
 	accountHandler := handler.NewAccountHandler(accountService)
+	productHandler := handler.NewProductHandler(productService)
+	arrearsHandler := handler.NewArrearsHandler(arrearsService)
+	reportHandler := handler.NewReportHandler(reportService)
+	penaltyHandler := handler.NewPenaltyHandler(penaltyService)
+	statementHandler := handler.NewStatementHandler(statementService)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+	suspenseHandler := handler.NewSuspenseHandler(suspenseService)
+	assetHandler := handler.NewAssetHandler(assetService)
+	guarantorHandler := handler.NewGuarantorHandler(guarantorService)
 
 	// Apply logging middleware
 	router.Use(middleware.LoggingMiddleware)
@@ -35,17 +55,76 @@ func NewRouter(
 	api.HandleFunc("/customers/{id}", customerHandler.UpdateCustomer).Methods("PUT")
 	api.HandleFunc("/customers/{id}", customerHandler.DeleteCustomer).Methods("DELETE")
 
+	// Guarantor routes
+	api.HandleFunc("/customers/{id}/guarantors", guarantorHandler.CreateGuarantor).Methods("POST")
+	api.HandleFunc("/customers/{id}/guarantors", guarantorHandler.GetGuarantors).Methods("GET")
+	api.HandleFunc("/customers/{id}/guarantors/{guarantor_id}", guarantorHandler.GetGuarantor).Methods("GET")
+	api.HandleFunc("/customers/{id}/guarantors/{guarantor_id}", guarantorHandler.UpdateGuarantor).Methods("PUT")
+	api.HandleFunc("/customers/{id}/guarantors/{guarantor_id}", guarantorHandler.DeleteGuarantor).Methods("DELETE")
+	api.HandleFunc("/customers/{id}/guarantors/{guarantor_id}/verify", guarantorHandler.VerifyGuarantor).Methods("POST")
+
 	// Payment routes
-	api.HandleFunc("/payments/notify", paymentHandler.ProcessPaymentNotification).Methods("POST")
+	notifyHandler := webhookVerifier.Middleware(http.HandlerFunc(paymentHandler.ProcessPaymentNotification))
+	api.Handle("/payments/notify", notifyHandler).Methods("POST")
+	api.Handle("/payments/notify/{provider}", notifyHandler).Methods("POST")
+	api.Handle("/payments/import", adminAuth.Middleware(http.HandlerFunc(paymentHandler.ImportPayments))).Methods("POST")
+
+	// Suspense routes
+	api.HandleFunc("/suspense", suspenseHandler.GetAll).Methods("GET")
+	api.HandleFunc("/suspense/{id}", suspenseHandler.GetByID).Methods("GET")
+	api.Handle("/suspense/{id}/match", adminAuth.Middleware(http.HandlerFunc(suspenseHandler.Match))).Methods("POST")
+	api.Handle("/suspense/{id}/refund", adminAuth.Middleware(http.HandlerFunc(suspenseHandler.Refund))).Methods("POST")
 
 	// Deployment routes
 	api.HandleFunc("/deployments", deploymentHandler.RecordDeployment).Methods("POST")
+	api.HandleFunc("/deployments", deploymentHandler.GetAllDeployments).Methods("GET")
+	api.HandleFunc("/deployments/{id}", deploymentHandler.GetDeploymentByID).Methods("GET")
+	api.HandleFunc("/deployments/{id}/schedule", deploymentHandler.GetSchedule).Methods("GET")
+	api.HandleFunc("/deployments/{id}/repossess", deploymentHandler.RepossessDeployment).Methods("POST")
+	api.HandleFunc("/customers/{id}/deployments", deploymentHandler.GetDeploymentsByCustomer).Methods("GET")
 
 	// Transaction routes
 	api.HandleFunc("/customers/{id}/transactions", transactionHandler.GetTransactionsByCustomer).Methods("GET")
+	api.Handle("/transactions/{id}/reverse", adminAuth.Middleware(http.HandlerFunc(transactionHandler.ReverseTransaction))).Methods("POST")
+	api.HandleFunc("/transactions/{id}/allocations", transactionHandler.GetAllocations).Methods("GET")
 
 	// Account routes
 	api.HandleFunc("/customers/{id}/account", accountHandler.GetAccountByCustomer).Methods("GET")
+	api.HandleFunc("/customers/{id}/account/history", accountHandler.GetBalanceHistory).Methods("GET")
+	api.HandleFunc("/customers/{id}/statement", statementHandler.GetStatement).Methods("GET")
+	api.HandleFunc("/customers/{id}/ledger", accountHandler.GetLedgerByCustomer).Methods("GET")
+
+	// Arrears routes
+	api.HandleFunc("/arrears", arrearsHandler.GetArrears).Methods("GET")
+
+	// Report routes
+	api.HandleFunc("/reports/portfolio", reportHandler.GetPortfolioReport).Methods("GET")
+
+	// Penalty routes
+	api.HandleFunc("/penalty-rules", penaltyHandler.CreateRule).Methods("POST")
+	api.HandleFunc("/penalty-rules", penaltyHandler.GetAllRules).Methods("GET")
+	api.HandleFunc("/penalty-rules/{id}", penaltyHandler.GetRuleByID).Methods("GET")
+	api.HandleFunc("/penalty-rules/{id}", penaltyHandler.UpdateRule).Methods("PUT")
+	api.HandleFunc("/penalty-rules/{id}", penaltyHandler.DeleteRule).Methods("DELETE")
+	api.Handle("/penalties/evaluate", adminAuth.Middleware(http.HandlerFunc(penaltyHandler.EvaluatePenalties))).Methods("POST")
+
+	// Admin routes
+	api.Handle("/admin/reconcile", adminAuth.Middleware(http.HandlerFunc(reconciliationHandler.Reconcile))).Methods("POST")
+
+	// Product routes
+	api.HandleFunc("/products", productHandler.CreateProduct).Methods("POST")
+	api.HandleFunc("/products", productHandler.GetAllProducts).Methods("GET")
+	api.HandleFunc("/products/{id}", productHandler.GetProductByID).Methods("GET")
+	api.HandleFunc("/products/{id}", productHandler.UpdateProduct).Methods("PUT")
+	api.HandleFunc("/products/{id}", productHandler.DeleteProduct).Methods("DELETE")
+
+	// Asset routes
+	api.HandleFunc("/assets", assetHandler.CreateAsset).Methods("POST")
+	api.HandleFunc("/assets", assetHandler.GetAllAssets).Methods("GET")
+	api.HandleFunc("/assets/{id}", assetHandler.GetAssetByID).Methods("GET")
+	api.HandleFunc("/assets/{id}", assetHandler.UpdateAsset).Methods("PUT")
+	api.HandleFunc("/assets/{id}", assetHandler.DeleteAsset).Methods("DELETE")
+	api.HandleFunc("/assets/{id}/deployments", assetHandler.GetAssetDeployments).Methods("GET")
 
 	// Health check
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
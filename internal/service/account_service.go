@@ -1,25 +1,110 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/emmrys-jay/gigmile/internal/repository"
 )
 
 type AccountService interface {
-	GetAccountByCustomer(customerID int64) (*models.Account, error)
+	// GetAccountByCustomer returns the customer's account with its current
+	// arrears. When asOf is set, the balance is the one at that instant.
+	GetAccountByCustomer(customerID int64, asOf *time.Time) (*models.Account, error)
+	GetBalanceHistory(customerID int64, from, to time.Time, interval models.BalanceInterval) (*models.BalanceHistory, error)
+	GetLedgerByCustomer(customerID int64) ([]*models.LedgerEntry, error)
 }
 
+// maxBalancePoints bounds the length of a balance history
+const maxBalancePoints = 1000
+
 type accountService struct {
-	accountRepo repository.AccountRepository
+	accountRepo     repository.AccountRepository
+	ledgerRepo      repository.LedgerRepository
+	arrearsRepo     repository.ArrearsRepository
+	transactionRepo repository.TransactionRepository
 }
 
-func NewAccountService(accountRepo repository.AccountRepository) AccountService {
+func NewAccountService(
+	accountRepo repository.AccountRepository,
+	ledgerRepo repository.LedgerRepository,
+	arrearsRepo repository.ArrearsRepository,
+	transactionRepo repository.TransactionRepository,
+) AccountService {
 	return &accountService{
-		accountRepo: accountRepo,
+		accountRepo:     accountRepo,
+		ledgerRepo:      ledgerRepo,
+		arrearsRepo:     arrearsRepo,
+		transactionRepo: transactionRepo,
 	}
 }
 
-func (s *accountService) GetAccountByCustomer(customerID int64) (*models.Account, error) {
-	return s.accountRepo.GetByCustomerID(customerID)
+func (s *accountService) GetAccountByCustomer(customerID int64, asOf *time.Time) (*models.Account, error) {
+	account, err := s.accountRepo.GetByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asOf != nil {
+		account.Balance, err = s.transactionRepo.GetBalanceAsOf(account.ID, *asOf)
+		if err != nil {
+			return nil, err
+		}
+		account.AsOf = asOf
+	}
+
+	account.Arrears, err = s.arrearsRepo.GetByCustomerID(customerID, truncateToDate(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// GetBalanceHistory returns the customer's closing balance on each date from
+// from to to. Balances are rebuilt from transaction dates, so a back-dated
+// transaction changes the history from its transaction_date onwards.
+func (s *accountService) GetBalanceHistory(customerID int64, from, to time.Time, interval models.BalanceInterval) (*models.BalanceHistory, error) {
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+
+	if to.Before(from) {
+		return nil, errors.New("from must not be after to")
+	}
+	if !interval.IsValid() {
+		return nil, errors.New("unsupported interval, expected day, week or month")
+	}
+	if interval == models.BalanceIntervalDay && to.Sub(from).Hours()/24 >= maxBalancePoints {
+		return nil, fmt.Errorf("date range is too long, at most %d days are allowed", maxBalancePoints)
+	}
+
+	account, err := s.accountRepo.GetByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := s.transactionRepo.GetBalanceHistory(account.ID, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BalanceHistory{
+		CustomerID: customerID,
+		AccountID:  account.ID,
+		From:       from,
+		To:         to,
+		Interval:   interval,
+		Points:     points,
+	}, nil
 }
 
+func (s *accountService) GetLedgerByCustomer(customerID int64) ([]*models.LedgerEntry, error) {
+	// Ensure the customer has an account before listing its entries
+	if _, err := s.accountRepo.GetByCustomerID(customerID); err != nil {
+		return nil, err
+	}
+
+	return s.ledgerRepo.GetByCustomerID(customerID)
+}
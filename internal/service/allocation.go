@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+// SplitPayment spreads amount over the open installments, which must be sorted
+// by due date. The strategy decides whether the earliest (FIFO) or latest
+// (LIFO) installment is paid first; within an installment penalties are paid
+// before fees and fees before principal. The installments are updated in place
+// and the returned allocations are not yet stored. Any amount left once every
+// installment is paid is returned as unallocated.
+func SplitPayment(amount models.Money, installments []*models.Installment, strategy models.AllocationStrategy, paidAt time.Time) ([]*models.PaymentAllocation, models.Money) {
+	ordered := make([]*models.Installment, len(installments))
+	copy(ordered, installments)
+	if strategy == models.AllocationStrategyLIFO {
+		for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+	}
+
+	allocations := []*models.PaymentAllocation{}
+	remaining := amount
+
+	for _, installment := range ordered {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		components := []struct {
+			component   models.AllocationComponent
+			outstanding models.Money
+			paid        *models.Money
+		}{
+			{models.AllocationComponentPenalty, installment.PenaltyOutstanding(), &installment.PenaltyPaid},
+			{models.AllocationComponentFee, installment.FeeOutstanding(), &installment.FeePaid},
+			{models.AllocationComponentPrincipal, installment.PrincipalOutstanding(), &installment.AmountPaid},
+		}
+
+		for _, c := range components {
+			if !remaining.IsPositive() || !c.outstanding.IsPositive() {
+				continue
+			}
+
+			paid := min(remaining, c.outstanding)
+			*c.paid += paid
+			remaining -= paid
+
+			allocations = append(allocations, &models.PaymentAllocation{
+				ScheduleID:    installment.ScheduleID,
+				InstallmentID: installment.ID,
+				Component:     c.component,
+				Amount:        paid,
+			})
+		}
+
+		switch {
+		case !installment.Outstanding().IsPositive():
+			installment.Status = models.InstallmentStatusPaid
+			installment.PaidAt = &paidAt
+		case installment.AmountPaid+installment.FeePaid+installment.PenaltyPaid > 0:
+			installment.Status = models.InstallmentStatusPartial
+		}
+	}
+
+	return allocations, remaining
+}
+
+// allocatePayment splits a credited payment across the account's open
+// installments and stores the result. Schedules whose installments are all
+// paid are settled, and so are the deployment transaction and deployment
+// behind them; a defaulted deployment with nothing left overdue becomes active
+// again. It must run inside the unit
+// of work that credited the payment.
+func allocatePayment(repos *repository.Repositories, strategy models.AllocationStrategy, payment *models.Transaction) ([]*models.PaymentAllocation, error) {
+	installments, err := repos.Schedules.LockOpenInstallments(payment.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, _ := SplitPayment(payment.Amount, installments, strategy, time.Now())
+	if len(allocations) == 0 {
+		return allocations, nil
+	}
+
+	stored := make([]*models.PaymentAllocation, 0, len(allocations))
+	paidInstallments := map[int64]bool{}
+	scheduleIDs := []int64{}
+	for _, allocation := range allocations {
+		allocation.TransactionID = payment.ID
+		created, err := repos.Allocations.Create(allocation)
+		if err != nil {
+			return nil, err
+		}
+		stored = append(stored, created)
+
+		paidInstallments[allocation.InstallmentID] = true
+		if !slices.Contains(scheduleIDs, allocation.ScheduleID) {
+			scheduleIDs = append(scheduleIDs, allocation.ScheduleID)
+		}
+	}
+
+	for _, installment := range installments {
+		if !paidInstallments[installment.ID] {
+			continue
+		}
+		if err := repos.Schedules.UpdateInstallmentPayment(installment); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := settleSchedules(repos, scheduleIDs); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// settleSchedules settles the schedules whose installments are all paid,
+// together with the deployment transaction and deployment behind them; a
+// defaulted deployment with nothing left overdue becomes active again
+func settleSchedules(repos *repository.Repositories, scheduleIDs []int64) error {
+	today := truncateToDate(time.Now())
+	for _, scheduleID := range scheduleIDs {
+		settled, err := repos.Schedules.SettleIfPaid(scheduleID)
+		if err != nil {
+			return err
+		}
+
+		schedule, err := repos.Schedules.GetByID(scheduleID)
+		if err != nil {
+			return err
+		}
+
+		if !settled {
+			// A defaulted deployment that has caught up is active again
+			if _, err := repos.Deployments.CureIfCurrent(schedule.DeploymentID, today); err != nil {
+				return err
+			}
+			continue
+		}
+
+		complete := models.PaymentStatusComplete
+		if _, err := repos.Transactions.Update(schedule.TransactionID, &models.UpdateTransactionRequest{Status: &complete}); err != nil {
+			return fmt.Errorf("failed to settle deployment: %w", err)
+		}
+
+		// A repossessed deployment stays closed as repossessed once it is paid off
+		deployment, err := repos.Deployments.LockByID(schedule.DeploymentID)
+		if err != nil {
+			return err
+		}
+		if deployment.Status.CanTransitionTo(models.DeploymentStatusCompleted) {
+			if _, err := repos.Deployments.UpdateStatus(deployment.ID, models.DeploymentStatusCompleted, "Repaid in full"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// unallocatePayment undoes the allocations of a payment that is being
+// reversed. Negative allocations are stored against the reversal so reports as
+// of earlier dates still see the payment. Installments get their paid amounts
+// back, and schedules and deployments the payment settled are reopened. It
+// must run inside the unit of work that posts the reversal.
+func unallocatePayment(repos *repository.Repositories, payment, reversal *models.Transaction) error {
+	allocations, err := repos.Allocations.GetByTransactionID(payment.ID)
+	if err != nil {
+		return err
+	}
+
+	installments := map[int64]*models.Installment{}
+	installmentIDs := []int64{}
+	scheduleIDs := []int64{}
+	for _, allocation := range allocations {
+		installment, ok := installments[allocation.InstallmentID]
+		if !ok {
+			installment, err = repos.Schedules.LockInstallment(allocation.InstallmentID)
+			if err != nil {
+				return err
+			}
+			installments[installment.ID] = installment
+			installmentIDs = append(installmentIDs, installment.ID)
+		}
+
+		switch allocation.Component {
+		case models.AllocationComponentPenalty:
+			installment.PenaltyPaid -= allocation.Amount
+		case models.AllocationComponentFee:
+			installment.FeePaid -= allocation.Amount
+		case models.AllocationComponentPrincipal:
+			installment.AmountPaid -= allocation.Amount
+		}
+
+		if _, err := repos.Allocations.Create(&models.PaymentAllocation{
+			TransactionID: reversal.ID,
+			ScheduleID:    allocation.ScheduleID,
+			InstallmentID: allocation.InstallmentID,
+			Component:     allocation.Component,
+			Amount:        -allocation.Amount,
+		}); err != nil {
+			return err
+		}
+
+		if !slices.Contains(scheduleIDs, allocation.ScheduleID) {
+			scheduleIDs = append(scheduleIDs, allocation.ScheduleID)
+		}
+	}
+
+	for _, id := range installmentIDs {
+		installment := installments[id]
+		if !installment.Outstanding().IsPositive() {
+			continue
+		}
+
+		installment.Status = models.InstallmentStatusPending
+		if installment.AmountPaid+installment.FeePaid+installment.PenaltyPaid > 0 {
+			installment.Status = models.InstallmentStatusPartial
+		}
+		installment.PaidAt = nil
+
+		if err := repos.Schedules.UpdateInstallmentPayment(installment); err != nil {
+			return err
+		}
+	}
+
+	for _, scheduleID := range scheduleIDs {
+		if err := reopenSchedule(repos, scheduleID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reopenSchedule moves a settled schedule that is owed money again back to
+// ACTIVE, together with the deployment transaction and a COMPLETED deployment
+// behind it
+func reopenSchedule(repos *repository.Repositories, scheduleID int64) error {
+	reopened, err := repos.Schedules.Reopen(scheduleID)
+	if err != nil || !reopened {
+		return err
+	}
+
+	schedule, err := repos.Schedules.GetByID(scheduleID)
+	if err != nil {
+		return err
+	}
+
+	pending := models.PaymentStatusPending
+	if _, err := repos.Transactions.Update(schedule.TransactionID, &models.UpdateTransactionRequest{Status: &pending}); err != nil {
+		return fmt.Errorf("failed to reopen deployment transaction: %w", err)
+	}
+
+	// COMPLETED is terminal for status changes requested through the API, but a
+	// deployment whose repayment was clawed back is still owed
+	deployment, err := repos.Deployments.LockByID(schedule.DeploymentID)
+	if err != nil {
+		return err
+	}
+	if deployment.Status == models.DeploymentStatusCompleted {
+		if _, err := repos.Deployments.UpdateStatus(deployment.ID, models.DeploymentStatusActive, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
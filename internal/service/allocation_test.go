@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+// split is the expected allocation of one component of an installment
+type split struct {
+	installmentID int64
+	component     models.AllocationComponent
+	amount        models.Money
+}
+
+func TestSplitPayment(t *testing.T) {
+	penalty := models.AllocationComponentPenalty
+	fee := models.AllocationComponentFee
+	principal := models.AllocationComponentPrincipal
+
+	// Three installments of 1000 due in order; the first also owes a 50 fee
+	// and a 20 penalty
+	installments := func() []*models.Installment {
+		return []*models.Installment{
+			{ID: 1, ScheduleID: 9, AmountDue: 1000, FeeDue: 50, PenaltyDue: 20, Status: models.InstallmentStatusPending},
+			{ID: 2, ScheduleID: 9, AmountDue: 1000, Status: models.InstallmentStatusPending},
+			{ID: 3, ScheduleID: 9, AmountDue: 1000, Status: models.InstallmentStatusPending},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		amount      models.Money
+		strategy    models.AllocationStrategy
+		want        []split
+		unallocated models.Money
+		statuses    []models.InstallmentStatus
+	}{
+		{
+			name:     "FIFO pays penalty, fee, then principal",
+			amount:   570,
+			strategy: models.AllocationStrategyFIFO,
+			want:     []split{{1, penalty, 20}, {1, fee, 50}, {1, principal, 500}},
+			statuses: []models.InstallmentStatus{models.InstallmentStatusPartial, models.InstallmentStatusPending, models.InstallmentStatusPending},
+		},
+		{
+			name:     "FIFO partial penalty",
+			amount:   15,
+			strategy: models.AllocationStrategyFIFO,
+			want:     []split{{1, penalty, 15}},
+			statuses: []models.InstallmentStatus{models.InstallmentStatusPartial, models.InstallmentStatusPending, models.InstallmentStatusPending},
+		},
+		{
+			name:     "FIFO spills into the next installment",
+			amount:   1570,
+			strategy: models.AllocationStrategyFIFO,
+			want:     []split{{1, penalty, 20}, {1, fee, 50}, {1, principal, 1000}, {2, principal, 500}},
+			statuses: []models.InstallmentStatus{models.InstallmentStatusPaid, models.InstallmentStatusPartial, models.InstallmentStatusPending},
+		},
+		{
+			name:     "LIFO pays the latest installment first",
+			amount:   1500,
+			strategy: models.AllocationStrategyLIFO,
+			want:     []split{{3, principal, 1000}, {2, principal, 500}},
+			statuses: []models.InstallmentStatus{models.InstallmentStatusPending, models.InstallmentStatusPartial, models.InstallmentStatusPaid},
+		},
+		{
+			name:     "LIFO reaches the penalty last",
+			amount:   2030,
+			strategy: models.AllocationStrategyLIFO,
+			want:     []split{{3, principal, 1000}, {2, principal, 1000}, {1, penalty, 20}, {1, fee, 10}},
+			statuses: []models.InstallmentStatus{models.InstallmentStatusPartial, models.InstallmentStatusPaid, models.InstallmentStatusPaid},
+		},
+		{
+			name:        "overpayment is returned as unallocated",
+			amount:      3500,
+			strategy:    models.AllocationStrategyFIFO,
+			want:        []split{{1, penalty, 20}, {1, fee, 50}, {1, principal, 1000}, {2, principal, 1000}, {3, principal, 1000}},
+			unallocated: 430,
+			statuses:    []models.InstallmentStatus{models.InstallmentStatusPaid, models.InstallmentStatusPaid, models.InstallmentStatusPaid},
+		},
+		{
+			name:     "zero amount allocates nothing",
+			amount:   0,
+			strategy: models.AllocationStrategyFIFO,
+			statuses: []models.InstallmentStatus{models.InstallmentStatusPending, models.InstallmentStatusPending, models.InstallmentStatusPending},
+		},
+	}
+
+	paidAt := time.Date(2025, time.January, 10, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		open := installments()
+		allocations, unallocated := SplitPayment(tt.amount, open, tt.strategy, paidAt)
+
+		if unallocated != tt.unallocated {
+			t.Errorf("%s: unallocated = %d, want %d", tt.name, unallocated, tt.unallocated)
+		}
+		if len(allocations) != len(tt.want) {
+			t.Errorf("%s: got %d allocations, want %d", tt.name, len(allocations), len(tt.want))
+			continue
+		}
+
+		var allocated models.Money
+		for i, allocation := range allocations {
+			want := tt.want[i]
+			if allocation.InstallmentID != want.installmentID || allocation.Component != want.component || allocation.Amount != want.amount {
+				t.Errorf("%s: allocation %d = installment %d %s %d, want installment %d %s %d", tt.name, i,
+					allocation.InstallmentID, allocation.Component, allocation.Amount,
+					want.installmentID, want.component, want.amount)
+			}
+			if allocation.ScheduleID != 9 {
+				t.Errorf("%s: allocation %d schedule = %d, want 9", tt.name, i, allocation.ScheduleID)
+			}
+			allocated += allocation.Amount
+		}
+		if allocated+unallocated != tt.amount {
+			t.Errorf("%s: allocated %d plus unallocated %d != amount %d", tt.name, allocated, unallocated, tt.amount)
+		}
+
+		for i, installment := range open {
+			if installment.Status != tt.statuses[i] {
+				t.Errorf("%s: installment %d status = %s, want %s", tt.name, installment.ID, installment.Status, tt.statuses[i])
+			}
+			paid := installment.Status == models.InstallmentStatusPaid
+			if paid != (installment.PaidAt != nil) {
+				t.Errorf("%s: installment %d is %s with paid_at %v", tt.name, installment.ID, installment.Status, installment.PaidAt)
+			}
+		}
+	}
+}
+
+func TestSplitPaymentKeepsInstallmentOrder(t *testing.T) {
+	installments := []*models.Installment{
+		{ID: 1, AmountDue: 100},
+		{ID: 2, AmountDue: 100},
+	}
+
+	SplitPayment(100, installments, models.AllocationStrategyLIFO, time.Now())
+
+	if installments[0].ID != 1 || installments[1].ID != 2 {
+		t.Errorf("LIFO reordered the caller's installments")
+	}
+	if installments[1].AmountPaid != 100 || installments[0].AmountPaid != 0 {
+		t.Errorf("LIFO paid %d and %d, want 0 and 100", installments[0].AmountPaid, installments[1].AmountPaid)
+	}
+}
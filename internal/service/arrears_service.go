@@ -0,0 +1,49 @@
+package service
+
+import (
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+type ArrearsService interface {
+	GetArrears(bucket *models.DelinquencyBucket) ([]*models.Arrears, error)
+	GetArrearsByCustomer(customerID int64) (*models.Arrears, error)
+}
+
+type arrearsService struct {
+	arrearsRepo repository.ArrearsRepository
+}
+
+func NewArrearsService(arrearsRepo repository.ArrearsRepository) ArrearsService {
+	return &arrearsService{
+		arrearsRepo: arrearsRepo,
+	}
+}
+
+// GetArrears lists customers with overdue installments as of today, optionally
+// limited to one delinquency bucket
+func (s *arrearsService) GetArrears(bucket *models.DelinquencyBucket) ([]*models.Arrears, error) {
+	arrears, err := s.arrearsRepo.GetAll(truncateToDate(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	if bucket == nil {
+		return arrears, nil
+	}
+
+	filtered := []*models.Arrears{}
+	for _, item := range arrears {
+		if item.Bucket == *bucket {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (s *arrearsService) GetArrearsByCustomer(customerID int64) (*models.Arrears, error) {
+	return s.arrearsRepo.GetByCustomerID(customerID, truncateToDate(time.Now()))
+}
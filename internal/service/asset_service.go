@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+var (
+	// ErrAssetUnavailable is returned when a deployment names an asset that is
+	// not IN_STOCK
+	ErrAssetUnavailable = errors.New("asset is not in stock")
+	// ErrAssetInUse is returned when an asset on an open deployment would be
+	// changed out from under it
+	ErrAssetInUse = errors.New("asset is on an open deployment")
+)
+
+type AssetService interface {
+	CreateAsset(assetReq *models.CreateAssetRequest) (*models.Asset, error)
+	GetAssetByID(id int64) (*models.Asset, error)
+	GetAllAssets(status *models.AssetStatus) ([]*models.Asset, error)
+	// GetDeployments lists who the asset has been deployed to, newest first
+	GetDeployments(id int64) ([]*models.Deployment, error)
+	UpdateAsset(id int64, assetReq *models.UpdateAssetRequest) (*models.Asset, error)
+	DeleteAsset(id int64) error
+}
+
+type assetService struct {
+	assetRepo      repository.AssetRepository
+	productRepo    repository.ProductRepository
+	deploymentRepo repository.DeploymentRepository
+	uow            repository.UnitOfWork
+}
+
+func NewAssetService(
+	assetRepo repository.AssetRepository,
+	productRepo repository.ProductRepository,
+	deploymentRepo repository.DeploymentRepository,
+	uow repository.UnitOfWork,
+) AssetService {
+	return &assetService{
+		assetRepo:      assetRepo,
+		productRepo:    productRepo,
+		deploymentRepo: deploymentRepo,
+		uow:            uow,
+	}
+}
+
+func (s *assetService) CreateAsset(assetReq *models.CreateAssetRequest) (*models.Asset, error) {
+	if normalizeIdentifier(assetReq.VIN) == "" {
+		return nil, errors.New("vin cannot be empty")
+	}
+
+	asset := &models.Asset{
+		VIN:          normalizeIdentifier(assetReq.VIN),
+		Make:         strings.TrimSpace(assetReq.Make),
+		Model:        strings.TrimSpace(assetReq.Model),
+		PurchaseCost: assetReq.PurchaseCost,
+	}
+
+	if assetReq.PlateNumber != nil {
+		asset.PlateNumber = plateNumber(*assetReq.PlateNumber)
+	}
+
+	if assetReq.ProductID != "" {
+		productID, err := s.resolveProduct(assetReq.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		asset.ProductID = &productID
+	}
+
+	asset, err := s.assetRepo.Create(asset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (s *assetService) GetAssetByID(id int64) (*models.Asset, error) {
+	return s.assetRepo.GetByID(id)
+}
+
+func (s *assetService) GetAllAssets(status *models.AssetStatus) ([]*models.Asset, error) {
+	return s.assetRepo.GetAll(status)
+}
+
+func (s *assetService) GetDeployments(id int64) ([]*models.Deployment, error) {
+	if _, err := s.assetRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.deploymentRepo.GetByAssetID(id)
+}
+
+// UpdateAsset changes the asset's details. The status of an asset on an open
+// deployment is managed by the deployment, so it cannot be changed here.
+func (s *assetService) UpdateAsset(id int64, assetReq *models.UpdateAssetRequest) (*models.Asset, error) {
+	if assetReq.VIN != nil && normalizeIdentifier(*assetReq.VIN) == "" {
+		return nil, errors.New("vin cannot be empty")
+	}
+
+	var productID *int64
+	if assetReq.ProductID != nil && *assetReq.ProductID != "" {
+		resolved, err := s.resolveProduct(*assetReq.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		productID = &resolved
+	}
+
+	var asset *models.Asset
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		var err error
+		asset, err = repos.Assets.LockByID(id)
+		if err != nil {
+			return err
+		}
+
+		if assetReq.Status != nil && *assetReq.Status != asset.Status {
+			if err := checkAssetNotInUse(repos, asset); err != nil {
+				return err
+			}
+			asset.Status = *assetReq.Status
+		}
+
+		// An empty product_id unlinks the asset from its product
+		if assetReq.ProductID != nil {
+			asset.ProductID = productID
+		}
+		if assetReq.VIN != nil {
+			asset.VIN = normalizeIdentifier(*assetReq.VIN)
+		}
+		// An empty plate_number clears it, e.g. when an asset is deregistered
+		if assetReq.PlateNumber != nil {
+			asset.PlateNumber = plateNumber(*assetReq.PlateNumber)
+		}
+		if assetReq.Make != nil {
+			asset.Make = strings.TrimSpace(*assetReq.Make)
+		}
+		if assetReq.Model != nil {
+			asset.Model = strings.TrimSpace(*assetReq.Model)
+		}
+		if assetReq.PurchaseCost != nil {
+			asset.PurchaseCost = *assetReq.PurchaseCost
+		}
+
+		asset, err = repos.Assets.Update(asset)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+func (s *assetService) DeleteAsset(id int64) error {
+	return s.uow.Do(func(repos *repository.Repositories) error {
+		asset, err := repos.Assets.LockByID(id)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAssetNotInUse(repos, asset); err != nil {
+			return err
+		}
+
+		return repos.Assets.Delete(id)
+	})
+}
+
+func (s *assetService) resolveProduct(productRef string) (int64, error) {
+	productID, err := utils.ParseProductID(productRef)
+	if err != nil {
+		return 0, fmt.Errorf("invalid product_id: %w", err)
+	}
+
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return 0, fmt.Errorf("product not found: %w", err)
+	}
+
+	return productID, nil
+}
+
+// checkAssetNotInUse returns ErrAssetInUse when the asset is DEPLOYED on an
+// ACTIVE or DEFAULTED deployment. An asset left DEPLOYED by a completed
+// deployment is with a rider who has paid it off and may be changed.
+func checkAssetNotInUse(repos *repository.Repositories, asset *models.Asset) error {
+	if asset.Status != models.AssetStatusDeployed {
+		return nil
+	}
+
+	deployments, err := repos.Deployments.GetByAssetID(asset.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range deployments {
+		if !deployment.Status.IsTerminal() {
+			return fmt.Errorf("%w: %s is on deployment %s", ErrAssetInUse, utils.FormatAssetID(asset.ID), utils.FormatDeploymentID(deployment.ID))
+		}
+	}
+
+	return nil
+}
+
+// releaseAsset moves the asset of a deployment that has been closed without
+// being paid off to status. Deployments recorded before the asset registry
+// have no asset.
+func releaseAsset(repos *repository.Repositories, deployment *models.Deployment, status models.AssetStatus) error {
+	if deployment.AssetID == nil {
+		return nil
+	}
+
+	return repos.Assets.UpdateStatus(*deployment.AssetID, status)
+}
+
+// plateNumber normalizes a plate number, treating an empty one as none
+func plateNumber(value string) *string {
+	normalized := normalizeIdentifier(value)
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// normalizeIdentifier upper-cases chassis and plate numbers so the same
+// vehicle cannot be registered twice under different spellings
+func normalizeIdentifier(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
@@ -18,13 +18,13 @@ type CustomerService interface {
 
 type customerService struct {
 	customerRepo repository.CustomerRepository
-	accountRepo  repository.AccountRepository
+	uow          repository.UnitOfWork
 }
 
-func NewCustomerService(customerRepo repository.CustomerRepository, accountRepo repository.AccountRepository) CustomerService {
+func NewCustomerService(customerRepo repository.CustomerRepository, uow repository.UnitOfWork) CustomerService {
 	return &customerService{
 		customerRepo: customerRepo,
-		accountRepo:  accountRepo,
+		uow:          uow,
 	}
 }
 
@@ -32,20 +32,29 @@ func (s *customerService) CreateCustomer(customerReq *models.CreateCustomerReque
 	// Normalize email
 	customerReq.Email = strings.ToLower(strings.TrimSpace(customerReq.Email))
 
-	// Create customer
-	customer, err := s.customerRepo.Create(customerReq)
+	// Create the customer and its account atomically
+	var customer *models.Customer
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		var err error
+		customer, err = repos.Customers.Create(customerReq)
+		if err != nil {
+			return fmt.Errorf("failed to create customer: %w", err)
+		}
+
+		// Create account for the customer
+		createAccountReq := &models.CreateAccountRequest{
+			CustomerID: customer.ID,
+			Balance:    0,
+		}
+		_, err = repos.Accounts.Create(createAccountReq)
+		if err != nil {
+			return fmt.Errorf("failed to create account for customer: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create customer: %w", err)
-	}
-
-	// Create account for the customer
-	createAccountReq := &models.CreateAccountRequest{
-		CustomerID: customer.ID,
-		Balance:    0.00,
-	}
-	_, err = s.accountRepo.Create(createAccountReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create account for customer: %w", err)
+		return nil, err
 	}
 
 	return customer, nil
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/emmrys-jay/gigmile/internal/cache"
 	"github.com/emmrys-jay/gigmile/internal/models"
@@ -12,73 +14,221 @@ import (
 )
 
 type DeploymentService interface {
-	RecordDeployment(req *models.CreateDeploymentRequest) error
+	RecordDeployment(req *models.CreateDeploymentRequest) (*models.RepaymentSchedule, error)
+	GetSchedule(deploymentID int64) (*models.RepaymentSchedule, error)
+	// GetScheduleByTransaction looks a schedule up by its DEPLOYMENT
+	// transaction, which identified deployments before they had their own IDs
+	GetScheduleByTransaction(transactionID int64) (*models.RepaymentSchedule, error)
+	GetAll(status *models.DeploymentStatus) ([]*models.Deployment, error)
+	// GetByID returns the deployment with its repayment schedule
+	GetByID(id int64) (*models.Deployment, error)
+	GetByCustomerID(customerID int64) ([]*models.Deployment, error)
+	// Repossess closes an ACTIVE or DEFAULTED deployment whose asset has been
+	// taken back and marks the asset REPOSSESSED. The repayment schedule stays
+	// due.
+	Repossess(id int64, req *models.RepossessDeploymentRequest) (*models.Deployment, error)
+	// MarkDefaulted moves ACTIVE deployments with an installment that has been
+	// unpaid for at least days as of date to DEFAULTED
+	MarkDefaulted(date time.Time, days int) ([]*models.Deployment, error)
 }
 
+// ErrInvalidDeploymentTransition is returned when a deployment would move to
+// a status it cannot reach, such as COMPLETED to ACTIVE
+var ErrInvalidDeploymentTransition = errors.New("invalid deployment status transition")
+
 type deploymentService struct {
-	customerRepo    repository.CustomerRepository
-	accountRepo     repository.AccountRepository
-	transactionRepo repository.TransactionRepository
-	cache           cache.Cache
+	customerRepo   repository.CustomerRepository
+	accountRepo    repository.AccountRepository
+	productRepo    repository.ProductRepository
+	scheduleRepo   repository.ScheduleRepository
+	deploymentRepo repository.DeploymentRepository
+	uow            repository.UnitOfWork
+	cache          cache.Cache
+	policy         models.EligibilityPolicy
 }
 
 func NewDeploymentService(
 	customerRepo repository.CustomerRepository,
 	accountRepo repository.AccountRepository,
-	transactionRepo repository.TransactionRepository,
+	productRepo repository.ProductRepository,
+	scheduleRepo repository.ScheduleRepository,
+	deploymentRepo repository.DeploymentRepository,
+	uow repository.UnitOfWork,
 	cache cache.Cache,
+	policy *models.EligibilityPolicy,
 ) DeploymentService {
 	return &deploymentService{
-		customerRepo:    customerRepo,
-		accountRepo:     accountRepo,
-		transactionRepo: transactionRepo,
-		cache:           cache,
+		customerRepo:   customerRepo,
+		accountRepo:    accountRepo,
+		productRepo:    productRepo,
+		scheduleRepo:   scheduleRepo,
+		deploymentRepo: deploymentRepo,
+		uow:            uow,
+		cache:          cache,
+		policy:         *policy,
 	}
 }
 
-const DeploymentAmount = 1000000.00 // 1 million
-
-func (s *deploymentService) RecordDeployment(req *models.CreateDeploymentRequest) error {
+func (s *deploymentService) RecordDeployment(req *models.CreateDeploymentRequest) (*models.RepaymentSchedule, error) {
 	// Parse customer ID (remove GIG prefix if present)
 	customerID, err := utils.ParseCustomerID(req.CustomerID)
 	if err != nil {
-		return fmt.Errorf("invalid customer_id: %w", err)
+		return nil, fmt.Errorf("invalid customer_id: %w", err)
 	}
 
-	// Get customer
-	_, err = s.customerRepo.GetByID(customerID)
+	// Get customer; a soft-deleted customer is rejected by the eligibility rules
+	customer, err := s.customerRepo.GetByIDIncludingDeleted(customerID)
 	if err != nil {
-		return fmt.Errorf("customer not found: %w", err)
+		return nil, fmt.Errorf("customer not found: %w", err)
 	}
 
 	// Get customer's account
 	account, err := s.accountRepo.GetByCustomerID(customerID)
 	if err != nil {
-		return fmt.Errorf("account not found: %w", err)
+		return nil, fmt.Errorf("account not found: %w", err)
+	}
+
+	// Price the deployment from the product; the request may override its terms
+	productID, err := utils.ParseProductID(req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product_id: %w", err)
+	}
+
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	// The asset is optional, so deployments can still be recorded for vehicles
+	// that are not in the registry yet
+	var assetID *int64
+	if req.AssetID != "" {
+		id, err := utils.ParseAssetID(req.AssetID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset_id: %w", err)
+		}
+		assetID = &id
+	}
+
+	amount := product.Price
+	tenor := product.Tenor
+	if req.Tenor > 0 {
+		tenor = req.Tenor
+	}
+	frequency := product.Frequency
+	if req.Frequency != "" {
+		frequency = req.Frequency
+	}
+
+	// The first installment is due on start_date, or one period from today
+	startDate := addPeriods(truncateToDate(time.Now()), frequency, 1)
+	if req.StartDate != "" {
+		startDate, err = time.Parse(models.DateLayout, req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+	}
+
+	installments, err := GenerateInstallments(amount, tenor, frequency, startDate)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create transaction
 	if req.Description == "" {
-		req.Description = "Deployment"
+		req.Description = "Deployment: " + product.Name
 	}
 	createTransactionReq := &models.CreateTransactionRequest{
 		CustomerID:  customerID,
 		AccountID:   account.ID,
 		Reference:   req.Reference,
-		Amount:      DeploymentAmount,
+		Type:        models.TransactionTypeDeployment,
+		Direction:   models.DirectionDebit,
+		Amount:      amount,
 		Status:      models.PaymentStatusPending,
 		Description: req.Description,
 	}
 
-	transaction, err := s.transactionRepo.Create(createTransactionReq)
-	if err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
-	}
+	// Check eligibility, claim the asset, record the transaction and deployment,
+	// debit the account and store the repayment schedule atomically
+	var schedule *models.RepaymentSchedule
+	err = s.uow.Do(func(repos *repository.Repositories) error {
+		// Lock the account so concurrent deployments are checked one at a time
+		account, err := repos.Accounts.LockByID(account.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkEligibility(repos, customer, account, amount); err != nil {
+			return err
+		}
+
+		// Lock the asset so it cannot be handed to two riders at once
+		if assetID != nil {
+			asset, err := repos.Assets.LockByID(*assetID)
+			if err != nil {
+				return err
+			}
+			if asset.Status != models.AssetStatusInStock {
+				return fmt.Errorf("%w: %s is %s", ErrAssetUnavailable, utils.FormatAssetID(asset.ID), asset.Status)
+			}
+			if asset.ProductID != nil && *asset.ProductID != product.ID {
+				return fmt.Errorf("asset %s is a %s, not a %s", utils.FormatAssetID(asset.ID), utils.FormatProductID(*asset.ProductID), utils.FormatProductID(product.ID))
+			}
+		}
+
+		transaction, err := repos.Transactions.Create(createTransactionReq)
+		if err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		// Debit the account
+		err = repos.Accounts.Debit(account.ID, transaction.ID, amount)
+		if err != nil {
+			return fmt.Errorf("failed to debit account: %w", err)
+		}
+
+		deployment, err := repos.Deployments.Create(&models.Deployment{
+			CustomerID:    customerID,
+			AccountID:     account.ID,
+			ProductID:     &product.ID,
+			AssetID:       assetID,
+			TransactionID: transaction.ID,
+			Reference:     transaction.Reference,
+			Amount:        amount,
+			StartDate:     startDate,
+			EndDate:       installments[len(installments)-1].DueDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		if assetID != nil {
+			if err := repos.Assets.UpdateStatus(*assetID, models.AssetStatusDeployed); err != nil {
+				return err
+			}
+		}
+
+		schedule, err = repos.Schedules.Create(&models.RepaymentSchedule{
+			DeploymentID:  deployment.ID,
+			TransactionID: transaction.ID,
+			CustomerID:    customerID,
+			AccountID:     account.ID,
+			ProductID:     &product.ID,
+			Principal:     amount,
+			Tenor:         tenor,
+			Frequency:     frequency,
+			StartDate:     startDate,
+			Installments:  installments,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create repayment schedule: %w", err)
+		}
 
-	// Debit the account
-	err = s.accountRepo.Debit(account.ID, transaction.ID, DeploymentAmount)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to debit account: %w", err)
+		return nil, err
 	}
 
 	// Invalidate cache after successful debit
@@ -88,5 +238,105 @@ func (s *deploymentService) RecordDeployment(req *models.CreateDeploymentRequest
 		log.Printf("failed to invalidate cache: %v", err)
 	}
 
-	return nil
+	return schedule, nil
+}
+
+func (s *deploymentService) checkEligibility(repos *repository.Repositories, customer *models.Customer, account *models.Account, amount models.Money) error {
+	arrears, err := repos.Arrears.GetByCustomerID(customer.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	exposure, err := repos.Schedules.GetExposure(customer.ID)
+	if err != nil {
+		return err
+	}
+
+	verifiedGuarantors, err := repos.Guarantors.CountVerified(customer.ID)
+	if err != nil {
+		return err
+	}
+
+	return CheckEligibility(&s.policy, &models.EligibilityCheck{
+		Customer:           customer,
+		Account:            account,
+		Arrears:            arrears,
+		Exposure:           exposure,
+		Amount:             amount,
+		VerifiedGuarantors: verifiedGuarantors,
+	})
+}
+
+func (s *deploymentService) GetSchedule(deploymentID int64) (*models.RepaymentSchedule, error) {
+	return s.scheduleRepo.GetByDeploymentID(deploymentID)
+}
+
+func (s *deploymentService) GetScheduleByTransaction(transactionID int64) (*models.RepaymentSchedule, error) {
+	return s.scheduleRepo.GetByTransactionID(transactionID)
+}
+
+func (s *deploymentService) GetAll(status *models.DeploymentStatus) ([]*models.Deployment, error) {
+	return s.deploymentRepo.GetAll(status)
+}
+
+func (s *deploymentService) GetByID(id int64) (*models.Deployment, error) {
+	deployment, err := s.deploymentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deployments recorded before repayment schedules existed have none
+	schedule, err := s.scheduleRepo.GetByDeploymentID(id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	deployment.Schedule = schedule
+
+	return deployment, nil
+}
+
+func (s *deploymentService) GetByCustomerID(customerID int64) ([]*models.Deployment, error) {
+	if _, err := s.customerRepo.GetByID(customerID); err != nil {
+		return nil, err
+	}
+
+	return s.deploymentRepo.GetByCustomerID(customerID)
+}
+
+func (s *deploymentService) Repossess(id int64, req *models.RepossessDeploymentRequest) (*models.Deployment, error) {
+	var deployment *models.Deployment
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		var err error
+		deployment, err = transitionDeployment(repos, id, models.DeploymentStatusRepossessed, req.Reason)
+		if err != nil {
+			return err
+		}
+
+		return releaseAsset(repos, deployment, models.AssetStatusRepossessed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deployment, nil
+}
+
+func (s *deploymentService) MarkDefaulted(date time.Time, days int) ([]*models.Deployment, error) {
+	return s.deploymentRepo.MarkDefaulted(truncateToDate(date), days)
+}
+
+// transitionDeployment locks the deployment and moves it to status, refusing
+// transitions the deployment lifecycle does not allow. It must run inside a
+// unit of work.
+func transitionDeployment(repos *repository.Repositories, id int64, status models.DeploymentStatus, reason string) (*models.Deployment, error) {
+	deployment, err := repos.Deployments.LockByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !deployment.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("%w: deployment %s is %s and cannot move to %s", ErrInvalidDeploymentTransition, deployment.Reference, deployment.Status, status)
+	}
+
+	return repos.Deployments.UpdateStatus(id, status, reason)
 }
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+// NewEligibilityPolicy builds the deployment eligibility policy from its
+// configured values. Empty amounts and zero counts are not enforced.
+func NewEligibilityPolicy(maxConcurrentDeployments int, maxOutstandingExposure, minDeposit string, minVerifiedGuarantors int) (*models.EligibilityPolicy, error) {
+	policy := &models.EligibilityPolicy{MaxConcurrentDeployments: maxConcurrentDeployments}
+
+	if maxOutstandingExposure != "" {
+		amount, err := models.ParseMoney(maxOutstandingExposure)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_OUTSTANDING_EXPOSURE: %w", err)
+		}
+		policy.MaxOutstandingExposure = amount
+	}
+
+	if minDeposit != "" {
+		amount, err := models.ParseMoney(minDeposit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MIN_DEPLOYMENT_DEPOSIT: %w", err)
+		}
+		policy.MinDeposit = amount
+	}
+
+	// No customer could ever be eligible if more guarantors are required than
+	// a customer can have
+	if minVerifiedGuarantors > MaxGuarantors {
+		return nil, fmt.Errorf("invalid MIN_VERIFIED_GUARANTORS: a customer can have at most %d guarantors", MaxGuarantors)
+	}
+	policy.MinVerifiedGuarantors = minVerifiedGuarantors
+
+	return policy, nil
+}
+
+// eligibilityRule returns a violation when the check breaks the rule, or nil
+type eligibilityRule func(policy *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation
+
+// eligibilityRules are evaluated in order, and every violation is reported
+var eligibilityRules = []eligibilityRule{
+	customerNotDeleted,
+	customerNotInArrears,
+	maxConcurrentDeployments,
+	maxOutstandingExposure,
+	minDeposit,
+	minVerifiedGuarantors,
+}
+
+// CheckEligibility evaluates every eligibility rule and returns an
+// *models.EligibilityRejection listing the broken ones, or nil
+func CheckEligibility(policy *models.EligibilityPolicy, check *models.EligibilityCheck) error {
+	rejection := &models.EligibilityRejection{CustomerID: check.Customer.ID}
+	for _, rule := range eligibilityRules {
+		if violation := rule(policy, check); violation != nil {
+			rejection.Violations = append(rejection.Violations, violation)
+		}
+	}
+
+	if len(rejection.Violations) > 0 {
+		return rejection
+	}
+	return nil
+}
+
+func customerNotDeleted(_ *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation {
+	if check.Customer.DeletedAt == nil {
+		return nil
+	}
+
+	return &models.EligibilityViolation{
+		Rule:    models.EligibilityRuleCustomerDeleted,
+		Message: "customer has been deleted",
+	}
+}
+
+func customerNotInArrears(_ *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation {
+	if check.Arrears == nil || !check.Arrears.Amount.IsPositive() {
+		return nil
+	}
+
+	return &models.EligibilityViolation{
+		Rule:    models.EligibilityRuleCustomerInArrears,
+		Message: fmt.Sprintf("customer is %d days past due on %s", check.Arrears.DaysPastDue, check.Arrears.Amount),
+		Actual:  check.Arrears.Amount,
+	}
+}
+
+func maxConcurrentDeployments(policy *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation {
+	if policy.MaxConcurrentDeployments <= 0 || check.Exposure.ActiveDeployments < policy.MaxConcurrentDeployments {
+		return nil
+	}
+
+	return &models.EligibilityViolation{
+		Rule:    models.EligibilityRuleMaxConcurrentDeployments,
+		Message: fmt.Sprintf("customer already has %d active deployments, the maximum is %d", check.Exposure.ActiveDeployments, policy.MaxConcurrentDeployments),
+		Limit:   policy.MaxConcurrentDeployments,
+		Actual:  check.Exposure.ActiveDeployments,
+	}
+}
+
+// maxOutstandingExposure counts the requested deployment towards the exposure
+func maxOutstandingExposure(policy *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation {
+	exposure := check.Exposure.Outstanding + check.Amount
+	if policy.MaxOutstandingExposure <= 0 || exposure <= policy.MaxOutstandingExposure {
+		return nil
+	}
+
+	return &models.EligibilityViolation{
+		Rule:    models.EligibilityRuleMaxOutstandingExposure,
+		Message: fmt.Sprintf("outstanding exposure would be %s, the maximum is %s", exposure, policy.MaxOutstandingExposure),
+		Limit:   policy.MaxOutstandingExposure,
+		Actual:  exposure,
+	}
+}
+
+// minDeposit measures the deposit as the credits not yet allocated to an
+// installment. The wallet balance alone would not do: it is net of every
+// deployment debit, so it stays negative while an earlier deployment is being
+// repaid. Adding back what is still owed on the active schedules leaves only
+// the money paid in beyond that.
+func minDeposit(policy *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation {
+	deposit := check.Account.Balance + check.Exposure.Outstanding
+	if policy.MinDeposit <= 0 || deposit >= policy.MinDeposit {
+		return nil
+	}
+
+	return &models.EligibilityViolation{
+		Rule:    models.EligibilityRuleMinDeposit,
+		Message: fmt.Sprintf("unallocated deposit is %s, a deposit of at least %s is required", deposit, policy.MinDeposit),
+		Limit:   policy.MinDeposit,
+		Actual:  deposit,
+	}
+}
+
+func minVerifiedGuarantors(policy *models.EligibilityPolicy, check *models.EligibilityCheck) *models.EligibilityViolation {
+	if policy.MinVerifiedGuarantors <= 0 || check.VerifiedGuarantors >= policy.MinVerifiedGuarantors {
+		return nil
+	}
+
+	return &models.EligibilityViolation{
+		Rule:    models.EligibilityRuleMinVerifiedGuarantors,
+		Message: fmt.Sprintf("customer has %d verified guarantors, at least %d are required", check.VerifiedGuarantors, policy.MinVerifiedGuarantors),
+		Limit:   policy.MinVerifiedGuarantors,
+		Actual:  check.VerifiedGuarantors,
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+func TestCheckEligibility(t *testing.T) {
+	policy := &models.EligibilityPolicy{
+		MaxConcurrentDeployments: 2,
+		MaxOutstandingExposure:   1000000,
+		MinDeposit:               50000,
+		MinVerifiedGuarantors:    1,
+	}
+
+	// eligible returns a check that passes every rule of policy
+	eligible := func() *models.EligibilityCheck {
+		return &models.EligibilityCheck{
+			Customer: &models.Customer{ID: 7},
+			Account:  &models.Account{Balance: 60000},
+			Arrears:  &models.Arrears{},
+			Exposure: &models.Exposure{},
+			Amount:   500000,
+
+			VerifiedGuarantors: 1,
+		}
+	}
+	deletedAt := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		policy *models.EligibilityPolicy
+		modify func(check *models.EligibilityCheck)
+		want   []models.EligibilityRule
+	}{
+		{
+			name:   "eligible",
+			modify: func(check *models.EligibilityCheck) {},
+		},
+		{
+			name:   "no arrears record",
+			modify: func(check *models.EligibilityCheck) { check.Arrears = nil },
+		},
+		{
+			name:   "deleted customer",
+			modify: func(check *models.EligibilityCheck) { check.Customer.DeletedAt = &deletedAt },
+			want:   []models.EligibilityRule{models.EligibilityRuleCustomerDeleted},
+		},
+		{
+			name: "in arrears",
+			modify: func(check *models.EligibilityCheck) {
+				check.Arrears = &models.Arrears{Amount: 100, DaysPastDue: 3}
+			},
+			want: []models.EligibilityRule{models.EligibilityRuleCustomerInArrears},
+		},
+		{
+			name:   "at the deployment limit",
+			modify: func(check *models.EligibilityCheck) { check.Exposure.ActiveDeployments = 2 },
+			want:   []models.EligibilityRule{models.EligibilityRuleMaxConcurrentDeployments},
+		},
+		{
+			name:   "one below the deployment limit",
+			modify: func(check *models.EligibilityCheck) { check.Exposure.ActiveDeployments = 1 },
+		},
+		{
+			name: "requested deployment pushes exposure over the limit",
+			modify: func(check *models.EligibilityCheck) {
+				check.Exposure.Outstanding = 500001
+				check.Account.Balance = 60000 - 500001
+			},
+			want: []models.EligibilityRule{models.EligibilityRuleMaxOutstandingExposure},
+		},
+		{
+			name: "exposure exactly at the limit",
+			modify: func(check *models.EligibilityCheck) {
+				check.Exposure.Outstanding = 500000
+				check.Account.Balance = 60000 - 500000
+			},
+		},
+		{
+			name:   "deposit too small",
+			modify: func(check *models.EligibilityCheck) { check.Account.Balance = 49999 },
+			want:   []models.EligibilityRule{models.EligibilityRuleMinDeposit},
+		},
+		{
+			name: "repaying an earlier deployment is not a deposit",
+			modify: func(check *models.EligibilityCheck) {
+				// The first deployment of 400000 is half repaid: 200000 is paid in
+				// and allocated, so the balance is -200000 with 200000 still owed
+				check.Exposure = &models.Exposure{ActiveDeployments: 1, Outstanding: 200000}
+				check.Account.Balance = -200000
+			},
+			want: []models.EligibilityRule{models.EligibilityRuleMinDeposit},
+		},
+		{
+			name: "deposit paid while an earlier deployment is being repaid",
+			modify: func(check *models.EligibilityCheck) {
+				check.Exposure = &models.Exposure{ActiveDeployments: 1, Outstanding: 200000}
+				check.Account.Balance = -200000 + 50000
+			},
+		},
+		{
+			name:   "too few verified guarantors",
+			modify: func(check *models.EligibilityCheck) { check.VerifiedGuarantors = 0 },
+			want:   []models.EligibilityRule{models.EligibilityRuleMinVerifiedGuarantors},
+		},
+		{
+			name: "every broken rule is reported",
+			modify: func(check *models.EligibilityCheck) {
+				check.Customer.DeletedAt = &deletedAt
+				check.Arrears = &models.Arrears{Amount: 100}
+				check.Exposure = &models.Exposure{ActiveDeployments: 2, Outstanding: 900000}
+				check.Account.Balance = -900000
+				check.VerifiedGuarantors = 0
+			},
+			want: []models.EligibilityRule{
+				models.EligibilityRuleCustomerDeleted,
+				models.EligibilityRuleCustomerInArrears,
+				models.EligibilityRuleMaxConcurrentDeployments,
+				models.EligibilityRuleMaxOutstandingExposure,
+				models.EligibilityRuleMinDeposit,
+				models.EligibilityRuleMinVerifiedGuarantors,
+			},
+		},
+		{
+			name:   "zero limits are not enforced",
+			policy: &models.EligibilityPolicy{},
+			modify: func(check *models.EligibilityCheck) {
+				check.Exposure = &models.Exposure{ActiveDeployments: 5, Outstanding: 9000000}
+				check.Account.Balance = -9000000
+				check.VerifiedGuarantors = 0
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		check := eligible()
+		tt.modify(check)
+		p := policy
+		if tt.policy != nil {
+			p = tt.policy
+		}
+
+		err := CheckEligibility(p, check)
+		if len(tt.want) == 0 {
+			if err != nil {
+				t.Errorf("%s: returned error: %v", tt.name, err)
+			}
+			continue
+		}
+
+		var rejection *models.EligibilityRejection
+		if !errors.As(err, &rejection) {
+			t.Errorf("%s: error = %v, want *models.EligibilityRejection", tt.name, err)
+			continue
+		}
+		if rejection.CustomerID != 7 {
+			t.Errorf("%s: rejection customer = %d, want 7", tt.name, rejection.CustomerID)
+		}
+
+		var got []models.EligibilityRule
+		for _, violation := range rejection.Violations {
+			got = append(got, violation.Rule)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: violations = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewEligibilityPolicy(t *testing.T) {
+	policy, err := NewEligibilityPolicy(1, "1000000", "50000.50", 2)
+	if err != nil {
+		t.Fatalf("returned error: %v", err)
+	}
+	if policy.MaxConcurrentDeployments != 1 || policy.MaxOutstandingExposure != 100000000 || policy.MinDeposit != 5000050 || policy.MinVerifiedGuarantors != 2 {
+		t.Errorf("policy = %+v", policy)
+	}
+
+	tests := []struct {
+		name                   string
+		maxOutstandingExposure string
+		minDeposit             string
+		minVerifiedGuarantors  int
+	}{
+		{"invalid exposure", "lots", "", 0},
+		{"invalid deposit", "", "1,000", 0},
+		{"more guarantors than allowed", "", "", MaxGuarantors + 1},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewEligibilityPolicy(0, tt.maxOutstandingExposure, tt.minDeposit, tt.minVerifiedGuarantors); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
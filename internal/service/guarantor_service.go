@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+// MaxGuarantors is the most guarantors a customer can have
+const MaxGuarantors = 2
+
+// ErrGuarantorLimit is returned when a customer already has MaxGuarantors
+// guarantors
+var ErrGuarantorLimit = fmt.Errorf("a customer can have at most %d guarantors", MaxGuarantors)
+
+type GuarantorService interface {
+	CreateGuarantor(customerID int64, req *models.CreateGuarantorRequest) (*models.Guarantor, error)
+	GetGuarantors(customerID int64) ([]*models.Guarantor, error)
+	GetGuarantor(customerID, id int64) (*models.Guarantor, error)
+	// UpdateGuarantor changes the guarantor's details. Changing the name or
+	// phone number means a different person, so the guarantor must be
+	// verified again.
+	UpdateGuarantor(customerID, id int64, req *models.UpdateGuarantorRequest) (*models.Guarantor, error)
+	VerifyGuarantor(customerID, id int64, req *models.VerifyGuarantorRequest) (*models.Guarantor, error)
+	DeleteGuarantor(customerID, id int64) error
+}
+
+type guarantorService struct {
+	customerRepo  repository.CustomerRepository
+	guarantorRepo repository.GuarantorRepository
+	uow           repository.UnitOfWork
+}
+
+func NewGuarantorService(
+	customerRepo repository.CustomerRepository,
+	guarantorRepo repository.GuarantorRepository,
+	uow repository.UnitOfWork,
+) GuarantorService {
+	return &guarantorService{
+		customerRepo:  customerRepo,
+		guarantorRepo: guarantorRepo,
+		uow:           uow,
+	}
+}
+
+func (s *guarantorService) CreateGuarantor(customerID int64, req *models.CreateGuarantorRequest) (*models.Guarantor, error) {
+	if normalizeIdentifier(req.PhoneNumber) == "" {
+		return nil, errors.New("phone_number cannot be empty")
+	}
+
+	guarantor := &models.Guarantor{
+		CustomerID:   customerID,
+		FirstName:    strings.TrimSpace(req.FirstName),
+		LastName:     strings.TrimSpace(req.LastName),
+		PhoneNumber:  normalizeIdentifier(req.PhoneNumber),
+		Email:        req.Email,
+		Address:      req.Address,
+		Relationship: strings.ToUpper(strings.TrimSpace(req.Relationship)),
+	}
+
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		// Lock the customer so concurrent requests cannot exceed the limit
+		if _, err := repos.Customers.LockByID(customerID); err != nil {
+			return err
+		}
+
+		existing, err := repos.Guarantors.GetByCustomerID(customerID)
+		if err != nil {
+			return err
+		}
+		if len(existing) >= MaxGuarantors {
+			return ErrGuarantorLimit
+		}
+
+		guarantor, err = repos.Guarantors.Create(guarantor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return guarantor, nil
+}
+
+func (s *guarantorService) GetGuarantors(customerID int64) ([]*models.Guarantor, error) {
+	if _, err := s.customerRepo.GetByID(customerID); err != nil {
+		return nil, err
+	}
+
+	return s.guarantorRepo.GetByCustomerID(customerID)
+}
+
+func (s *guarantorService) GetGuarantor(customerID, id int64) (*models.Guarantor, error) {
+	return s.guarantorRepo.GetByID(customerID, id)
+}
+
+func (s *guarantorService) UpdateGuarantor(customerID, id int64, req *models.UpdateGuarantorRequest) (*models.Guarantor, error) {
+	var guarantor *models.Guarantor
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		var err error
+		guarantor, err = repos.Guarantors.LockByID(customerID, id)
+		if err != nil {
+			return err
+		}
+
+		identityChanged := false
+		if req.FirstName != nil {
+			firstName := strings.TrimSpace(*req.FirstName)
+			identityChanged = identityChanged || firstName != guarantor.FirstName
+			guarantor.FirstName = firstName
+		}
+		if req.LastName != nil {
+			lastName := strings.TrimSpace(*req.LastName)
+			identityChanged = identityChanged || lastName != guarantor.LastName
+			guarantor.LastName = lastName
+		}
+		if req.PhoneNumber != nil {
+			phoneNumber := normalizeIdentifier(*req.PhoneNumber)
+			if phoneNumber == "" {
+				return errors.New("phone_number cannot be empty")
+			}
+			identityChanged = identityChanged || phoneNumber != guarantor.PhoneNumber
+			guarantor.PhoneNumber = phoneNumber
+		}
+		if req.Email != nil {
+			guarantor.Email = req.Email
+		}
+		if req.Address != nil {
+			guarantor.Address = req.Address
+		}
+		if req.Relationship != nil {
+			guarantor.Relationship = strings.ToUpper(strings.TrimSpace(*req.Relationship))
+		}
+
+		if identityChanged {
+			guarantor.VerifiedAt = nil
+			guarantor.VerifiedBy = nil
+		}
+
+		guarantor, err = repos.Guarantors.Update(guarantor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return guarantor, nil
+}
+
+func (s *guarantorService) VerifyGuarantor(customerID, id int64, req *models.VerifyGuarantorRequest) (*models.Guarantor, error) {
+	return s.guarantorRepo.Verify(customerID, id, strings.TrimSpace(req.Actor))
+}
+
+func (s *guarantorService) DeleteGuarantor(customerID, id int64) error {
+	return s.guarantorRepo.Delete(customerID, id)
+}
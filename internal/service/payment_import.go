@@ -0,0 +1,254 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+// ErrInvalidImportFile is returned when a settlement file cannot be read or
+// lacks a mapped column
+var ErrInvalidImportFile = errors.New("invalid settlement file")
+
+// DefaultPaymentImportMapping expects the column names to match the payment
+// notification fields
+var DefaultPaymentImportMapping = models.PaymentImportMapping{
+	CustomerID: "customer_id",
+	Reference:  "transaction_reference",
+	Amount:     "transaction_amount",
+	Date:       "transaction_date",
+	Status:     "payment_status",
+	DateLayout: PaymentDateLayout,
+}
+
+// NewPaymentImportMapping builds the configured column mapping: columns is a
+// field=column list (see models.ParsePaymentImportMapping) applied on top of
+// DefaultPaymentImportMapping, and dateLayout replaces the default date layout
+// when set.
+func NewPaymentImportMapping(columns, dateLayout string) (*models.PaymentImportMapping, error) {
+	overrides, err := models.ParsePaymentImportMapping(columns)
+	if err != nil {
+		return nil, err
+	}
+	overrides.DateLayout = dateLayout
+
+	return DefaultPaymentImportMapping.Merge(overrides), nil
+}
+
+// importColumns holds the position of each mapped column in a settlement file.
+// status is -1 when the file has no status column.
+type importColumns struct {
+	customerID, reference, amount, date, status int
+}
+
+func (s *paymentService) ImportPayments(file io.Reader, provider string, overrides *models.PaymentImportMapping) (*models.PaymentImportReport, error) {
+	// Rows are keyed by provider like notifications are, so a row for a payment
+	// the provider already sent by webhook is a duplicate, not a new payment
+	if provider == "" {
+		provider = DefaultPaymentProvider
+	}
+	adapter, ok := paymentProviders[strings.ToLower(provider)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+
+	mapping := s.importMapping.Merge(overrides)
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+	}
+
+	columns, err := findImportColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PaymentImportReport{
+		Summary: map[models.PaymentImportRowStatus]int{},
+		Results: []*models.PaymentImportRow{},
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			// A malformed row does not stop the rows after it from being imported
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+			}
+			report.Add(&models.PaymentImportRow{
+				Line:   parseErr.StartLine,
+				Status: models.PaymentImportInvalid,
+				Error:  parseErr.Err.Error(),
+			})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		report.Add(s.importRow(adapter.Name(), line, record, columns, mapping))
+	}
+
+	return report, nil
+}
+
+func (s *paymentService) importRow(provider string, line int, record []string, columns importColumns, mapping *models.PaymentImportMapping) *models.PaymentImportRow {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := &models.PaymentImportRow{
+		Line:       line,
+		Reference:  field(columns.reference),
+		CustomerID: field(columns.customerID),
+		Amount:     field(columns.amount),
+	}
+
+	invalid := func(status models.PaymentImportRowStatus, err error) *models.PaymentImportRow {
+		row.Status = status
+		row.Error = err.Error()
+		return row
+	}
+
+	if row.Reference == "" {
+		return invalid(models.PaymentImportInvalid, errors.New("transaction_reference is required"))
+	}
+	if row.CustomerID == "" {
+		return invalid(models.PaymentImportUnknownCustomer, errors.New("customer_id is required"))
+	}
+
+	// Settlement files are completed payments unless they say otherwise
+	status := field(columns.status)
+	if status == "" {
+		status = string(models.PaymentStatusComplete)
+	}
+
+	amount, err := normalizeImportAmount(row.Amount)
+	if err != nil {
+		return invalid(models.PaymentImportInvalidAmount, err)
+	}
+
+	transactionDate, err := time.Parse(mapping.DateLayout, field(columns.date))
+	if err != nil {
+		return invalid(models.PaymentImportInvalid, fmt.Errorf("invalid transaction_date format: %w", err))
+	}
+
+	result, err := s.ProcessPaymentNotification(&models.PaymentNotificationRequest{
+		Provider:             provider,
+		CustomerID:           row.CustomerID,
+		PaymentStatus:        status,
+		TransactionAmount:    amount,
+		TransactionDate:      transactionDate.Format(PaymentDateLayout),
+		TransactionReference: row.Reference,
+	})
+	switch {
+	case errors.Is(err, ErrUnknownCustomer):
+		return invalid(models.PaymentImportUnknownCustomer, err)
+	case errors.Is(err, ErrInvalidAmount):
+		return invalid(models.PaymentImportInvalidAmount, err)
+	case err != nil:
+		return invalid(models.PaymentImportFailed, err)
+	}
+
+	row.TransactionID = result.TransactionID
+	if result.Status == models.NotificationStatusSuspended {
+		return invalid(models.PaymentImportUnknownCustomer, fmt.Errorf("%w: held as suspense payment %d", ErrUnknownCustomer, *result.SuspenseID))
+	}
+	if result.Replayed {
+		row.Status = models.PaymentImportDuplicate
+		return row
+	}
+
+	// Crediting happens later in the job worker, and only for completed payments
+	switch models.PaymentStatus(strings.ToUpper(status)) {
+	case models.PaymentStatusComplete:
+		row.Status = models.PaymentImportQueued
+	case models.PaymentStatusPending:
+		row.Status = models.PaymentImportPending
+	default:
+		row.Status = models.PaymentImportDeclined
+	}
+
+	return row
+}
+
+var (
+	plainAmount   = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]{1,2})?$`)
+	groupedAmount = regexp.MustCompile(`^[+-]?[0-9]{1,3}(,[0-9]{3})+(\.[0-9]{1,2})?$`)
+)
+
+// normalizeImportAmount strips the thousands separators settlement files often
+// write into amounts, as in "1,500.00". Amounts with more than two decimals or
+// commas that are not thousands separators, such as the decimal comma in
+// "1.000,50", are rejected rather than guessed at.
+func normalizeImportAmount(amount string) (string, error) {
+	switch {
+	case plainAmount.MatchString(amount):
+		return amount, nil
+	case groupedAmount.MatchString(amount):
+		return strings.ReplaceAll(amount, ",", ""), nil
+	}
+
+	return "", fmt.Errorf("%w: %q must use a dot for decimals, commas only between thousands, and at most two decimals", ErrInvalidAmount, amount)
+}
+
+// findImportColumns locates the mapped columns in the header row. Column names
+// are matched case-insensitively.
+func findImportColumns(header []string, mapping *models.PaymentImportMapping) (importColumns, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel prefixes UTF-8 CSV files with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	find := func(field, column string) (int, error) {
+		i, ok := positions[strings.ToLower(column)]
+		if !ok {
+			return 0, fmt.Errorf("%w: missing %s column %q", ErrInvalidImportFile, field, column)
+		}
+		return i, nil
+	}
+
+	var columns importColumns
+	var err error
+	if columns.customerID, err = find("customer_id", mapping.CustomerID); err != nil {
+		return columns, err
+	}
+	if columns.reference, err = find("transaction_reference", mapping.Reference); err != nil {
+		return columns, err
+	}
+	if columns.amount, err = find("transaction_amount", mapping.Amount); err != nil {
+		return columns, err
+	}
+	if columns.date, err = find("transaction_date", mapping.Date); err != nil {
+		return columns, err
+	}
+
+	columns.status = -1
+	if i, ok := positions[strings.ToLower(mapping.Status)]; ok && mapping.Status != "" {
+		columns.status = i
+	}
+
+	return columns, nil
+}
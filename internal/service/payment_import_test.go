@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+// The fakes embed the repository interfaces, so only the methods the import
+// path calls are implemented; any other call panics
+
+type missCache struct{}
+
+func (missCache) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("cache miss")
+}
+func (missCache) Set(context.Context, string, []byte, time.Duration) error { return nil }
+func (missCache) Delete(context.Context, string) error                     { return nil }
+func (missCache) Close() error                                             { return nil }
+
+type fakeAccounts struct {
+	repository.AccountRepository
+}
+
+func (fakeAccounts) GetByCustomerID(customerID int64) (*models.Account, error) {
+	return &models.Account{ID: customerID, CustomerID: customerID}, nil
+}
+
+type fakeNotifications struct {
+	repository.PaymentNotificationRepository
+	received []*models.PaymentNotification
+}
+
+func (f *fakeNotifications) GetByReference(provider, reference string, paymentStatus models.PaymentStatus) (*models.PaymentNotification, error) {
+	for _, n := range f.received {
+		if n.Provider == provider && n.Reference == reference && n.PaymentStatus == paymentStatus {
+			return n, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeNotifications) Create(notification *models.PaymentNotification) (*models.PaymentNotification, bool, error) {
+	notification.ID = int64(len(f.received) + 1)
+	f.received = append(f.received, notification)
+	return notification, true, nil
+}
+
+type fakeTransactions struct {
+	repository.TransactionRepository
+}
+
+func (fakeTransactions) GetByReference(string) (*models.Transaction, error) {
+	return nil, repository.ErrNotFound
+}
+
+type fakeJobs struct {
+	repository.JobRepository
+	enqueued int
+}
+
+func (f *fakeJobs) Enqueue(queue string, payload []byte, maxAttempts int) (*models.Job, error) {
+	f.enqueued++
+	return &models.Job{}, nil
+}
+
+type fakeUnitOfWork struct {
+	repos *repository.Repositories
+}
+
+func (u fakeUnitOfWork) Do(fn func(repos *repository.Repositories) error) error {
+	return fn(u.repos)
+}
+
+func TestImportPaymentsMatchesWebhookPayments(t *testing.T) {
+	const file = "customer_id,transaction_reference,transaction_amount,transaction_date\n" +
+		"GIG00001,T100,5000.00,2025-01-10 09:00:00\n" +
+		"GIG00001,T101,2500.00,2025-01-10 10:00:00\n"
+
+	tests := []struct {
+		name     string
+		provider string
+		want     []models.PaymentImportRowStatus
+	}{
+		{
+			name:     "row received by webhook from the same provider is a duplicate",
+			provider: "paystack",
+			want:     []models.PaymentImportRowStatus{models.PaymentImportDuplicate, models.PaymentImportQueued},
+		},
+		{
+			name:     "provider is matched case-insensitively",
+			provider: "Paystack",
+			want:     []models.PaymentImportRowStatus{models.PaymentImportDuplicate, models.PaymentImportQueued},
+		},
+		{
+			name:     "same reference from another provider is a different payment",
+			provider: "",
+			want:     []models.PaymentImportRowStatus{models.PaymentImportQueued, models.PaymentImportQueued},
+		},
+	}
+
+	for _, tt := range tests {
+		// T100 was already received from Paystack through /payments/notify/paystack
+		notifications := &fakeNotifications{received: []*models.PaymentNotification{{
+			ID:            1,
+			Provider:      "paystack",
+			Reference:     "T100",
+			PaymentStatus: models.PaymentStatusComplete,
+			Amount:        500000,
+			Status:        models.NotificationStatusProcessed,
+		}}}
+		jobs := &fakeJobs{}
+		uow := fakeUnitOfWork{repos: &repository.Repositories{
+			Payments:     notifications,
+			Transactions: fakeTransactions{},
+			Jobs:         jobs,
+		}}
+		s := NewPaymentService(nil, fakeAccounts{}, notifications, uow, missCache{}, models.AllocationStrategyFIFO, &DefaultPaymentImportMapping)
+
+		report, err := s.ImportPayments(strings.NewReader(file), tt.provider, nil)
+		if err != nil {
+			t.Errorf("%s: returned error: %v", tt.name, err)
+			continue
+		}
+
+		var got []models.PaymentImportRowStatus
+		for _, row := range report.Results {
+			got = append(got, row.Status)
+		}
+		if len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("%s: row statuses = %v, want %v", tt.name, got, tt.want)
+		}
+
+		queued := 0
+		for _, status := range tt.want {
+			if status == models.PaymentImportQueued {
+				queued++
+			}
+		}
+		if jobs.enqueued != queued {
+			t.Errorf("%s: enqueued %d jobs, want %d", tt.name, jobs.enqueued, queued)
+		}
+	}
+}
+
+func TestImportPaymentsUnknownProvider(t *testing.T) {
+	s := NewPaymentService(nil, fakeAccounts{}, &fakeNotifications{}, fakeUnitOfWork{}, missCache{}, models.AllocationStrategyFIFO, &DefaultPaymentImportMapping)
+
+	_, err := s.ImportPayments(strings.NewReader("customer_id\n"), "moniepoint", nil)
+	if !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("error = %v, want ErrUnknownProvider", err)
+	}
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	json "encoding/json/v2"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+// DefaultPaymentProvider sends notifications in the canonical
+// PaymentNotificationRequest format
+const DefaultPaymentProvider = "default"
+
+// ErrUnknownProvider is returned for a provider without an adapter
+var ErrUnknownProvider = errors.New("unknown payment provider")
+
+// ErrInvalidSignature is returned when a webhook is not signed with the
+// provider's secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+const (
+	// WebhookTimestampHeader carries the Unix time, in seconds, at which the
+	// default provider signed the request
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader carries the default provider's hex encoded
+	// HMAC-SHA256 signature
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// PaystackSignatureHeader carries Paystack's hex encoded HMAC-SHA512 of the body
+	PaystackSignatureHeader = "X-Paystack-Signature"
+	// FlutterwaveSignatureHeader carries the secret hash set on the Flutterwave dashboard
+	FlutterwaveSignatureHeader = "Verif-Hash"
+)
+
+// PaymentProvider adapts a provider's notification payload to the canonical
+// notification, so every provider goes through ProcessPaymentNotification
+type PaymentProvider interface {
+	// Name is the provider name used in /payments/notify/{provider}
+	Name() string
+	// ParseNotification translates the raw request body. Amounts are returned in
+	// naira and dates in PaymentDateLayout (UTC).
+	ParseNotification(body []byte) (*models.PaymentNotificationRequest, error)
+	// VerifySignature checks the request against the provider's own signing
+	// scheme, keyed with secret, and returns ErrInvalidSignature if it fails.
+	// tolerance applies to schemes that sign a timestamp.
+	VerifySignature(header http.Header, body []byte, secret string, tolerance time.Duration) error
+}
+
+// paymentProviders lists the available adapters by name. A new provider is
+// added by implementing PaymentProvider and listing it here.
+var paymentProviders = providersByName(
+	defaultProvider{},
+	paystackProvider{},
+	flutterwaveProvider{},
+)
+
+// PaymentProviders returns every available adapter
+func PaymentProviders() []PaymentProvider {
+	providers := make([]PaymentProvider, 0, len(paymentProviders))
+	for _, provider := range paymentProviders {
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+func providersByName(providers ...PaymentProvider) map[string]PaymentProvider {
+	byName := make(map[string]PaymentProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return byName
+}
+
+func (s *paymentService) ParseProviderNotification(provider string, body []byte) (*models.PaymentNotificationRequest, error) {
+	adapter, ok := paymentProviders[strings.ToLower(provider)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+
+	req, err := adapter.ParseNotification(body)
+	if err != nil {
+		return nil, err
+	}
+	req.Provider = adapter.Name()
+
+	return req, nil
+}
+
+// formatPaymentDate converts a provider timestamp to PaymentDateLayout in UTC
+func formatPaymentDate(t time.Time) string {
+	return t.UTC().Format(PaymentDateLayout)
+}
+
+// checkCurrency rejects payments in a currency other than naira; an empty
+// currency is taken to be naira
+func checkCurrency(provider, currency string) error {
+	if currency != "" && !strings.EqualFold(currency, "NGN") {
+		return fmt.Errorf("unsupported %s currency: %s", provider, currency)
+	}
+	return nil
+}
+
+// defaultProvider accepts the canonical notification as is. Its requests are
+// signed with the HMAC-SHA256 of the timestamp header, a dot and the raw body,
+// so a captured request cannot be replayed once it falls outside the tolerance.
+type defaultProvider struct{}
+
+func (defaultProvider) Name() string {
+	return DefaultPaymentProvider
+}
+
+func (defaultProvider) ParseNotification(body []byte) (*models.PaymentNotificationRequest, error) {
+	var req models.PaymentNotificationRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("invalid request payload: %w", err)
+	}
+	return &req, nil
+}
+
+func (defaultProvider) VerifySignature(header http.Header, body []byte, secret string, tolerance time.Duration) error {
+	timestamp := header.Get(WebhookTimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or invalid timestamp", ErrInvalidSignature)
+	}
+
+	age := time.Since(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(header.Get(WebhookSignatureHeader), "sha256="))
+	if err != nil || !hmac.Equal(signature, SignWebhook(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// SignWebhook computes the signature the default provider sends for body at timestamp
+func SignWebhook(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// paystackProvider reads Paystack charge events. Amounts are in kobo and the
+// customer ID is taken from data.metadata.customer_id. Paystack signs the body
+// with the HMAC-SHA512 of the secret key.
+type paystackProvider struct{}
+
+type paystackEvent struct {
+	Event string `json:"event"`
+	Data  struct {
+		Reference string `json:"reference"`
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+		Status    string `json:"status"`
+		PaidAt    string `json:"paid_at"`
+		CreatedAt string `json:"created_at"`
+		// Paystack sends an empty string when a charge has no metadata
+		Metadata any `json:"metadata"`
+	} `json:"data"`
+}
+
+var paystackStatuses = map[string]models.PaymentStatus{
+	"success":    models.PaymentStatusComplete,
+	"failed":     models.PaymentStatusFailed,
+	"abandoned":  models.PaymentStatusCancelled,
+	"pending":    models.PaymentStatusPending,
+	"ongoing":    models.PaymentStatusPending,
+	"processing": models.PaymentStatusPending,
+	"queued":     models.PaymentStatusPending,
+}
+
+func (paystackProvider) Name() string {
+	return "paystack"
+}
+
+func (p paystackProvider) ParseNotification(body []byte) (*models.PaymentNotificationRequest, error) {
+	var event paystackEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid paystack payload: %w", err)
+	}
+
+	// Transfers and refunds are payouts, not collections
+	if !strings.HasPrefix(event.Event, "charge.") {
+		return nil, fmt.Errorf("unsupported paystack event: %s", event.Event)
+	}
+	if err := checkCurrency(p.Name(), event.Data.Currency); err != nil {
+		return nil, err
+	}
+
+	paidAt := event.Data.PaidAt
+	if paidAt == "" {
+		paidAt = event.Data.CreatedAt
+	}
+	transactionDate, err := time.Parse(time.RFC3339, paidAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid paystack paid_at: %w", err)
+	}
+
+	return &models.PaymentNotificationRequest{
+		CustomerID:           metadataString(event.Data.Metadata, "customer_id"),
+		PaymentStatus:        mapProviderStatus(paystackStatuses, event.Data.Status),
+		TransactionAmount:    models.Money(event.Data.Amount).String(),
+		TransactionDate:      formatPaymentDate(transactionDate),
+		TransactionReference: event.Data.Reference,
+	}, nil
+}
+
+func (paystackProvider) VerifySignature(header http.Header, body []byte, secret string, tolerance time.Duration) error {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write(body)
+
+	signature, err := hex.DecodeString(header.Get(PaystackSignatureHeader))
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// flutterwaveProvider reads Flutterwave charge events, which cover bank
+// transfers and mobile money. Amounts are in naira and the customer ID is
+// taken from data.meta.customer_id. Flutterwave does not sign the body; it
+// sends the secret hash configured on its dashboard in a header.
+type flutterwaveProvider struct{}
+
+type flutterwaveEvent struct {
+	Event string `json:"event"`
+	Data  struct {
+		TxRef     string       `json:"tx_ref"`
+		Amount    models.Money `json:"amount"`
+		Currency  string       `json:"currency"`
+		Status    string       `json:"status"`
+		CreatedAt string       `json:"created_at"`
+		Meta      any          `json:"meta"`
+	} `json:"data"`
+}
+
+var flutterwaveStatuses = map[string]models.PaymentStatus{
+	"successful": models.PaymentStatusComplete,
+	"failed":     models.PaymentStatusFailed,
+	"cancelled":  models.PaymentStatusCancelled,
+	"pending":    models.PaymentStatusPending,
+}
+
+func (flutterwaveProvider) Name() string {
+	return "flutterwave"
+}
+
+func (flutterwaveProvider) VerifySignature(header http.Header, body []byte, secret string, tolerance time.Duration) error {
+	if subtle.ConstantTimeCompare([]byte(header.Get(FlutterwaveSignatureHeader)), []byte(secret)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (p flutterwaveProvider) ParseNotification(body []byte) (*models.PaymentNotificationRequest, error) {
+	var event flutterwaveEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid flutterwave payload: %w", err)
+	}
+
+	if event.Event != "charge.completed" {
+		return nil, fmt.Errorf("unsupported flutterwave event: %s", event.Event)
+	}
+	if err := checkCurrency(p.Name(), event.Data.Currency); err != nil {
+		return nil, err
+	}
+
+	transactionDate, err := time.Parse(time.RFC3339, event.Data.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flutterwave created_at: %w", err)
+	}
+
+	return &models.PaymentNotificationRequest{
+		CustomerID:           metadataString(event.Data.Meta, "customer_id"),
+		PaymentStatus:        mapProviderStatus(flutterwaveStatuses, event.Data.Status),
+		TransactionAmount:    event.Data.Amount.String(),
+		TransactionDate:      formatPaymentDate(transactionDate),
+		TransactionReference: event.Data.TxRef,
+	}, nil
+}
+
+// mapProviderStatus translates a provider status. An unknown status is passed
+// through so that ProcessPaymentNotification rejects it with the original value.
+func mapProviderStatus(statuses map[string]models.PaymentStatus, status string) string {
+	if mapped, ok := statuses[strings.ToLower(status)]; ok {
+		return string(mapped)
+	}
+	return status
+}
+
+// metadataString reads a string or number from a free-form metadata object
+func metadataString(metadata any, key string) string {
+	fields, ok := metadata.(map[string]any)
+	if !ok {
+		return ""
+	}
+
+	switch value := fields[key].(type) {
+	case string:
+		return value
+	case float64:
+		return fmt.Sprintf("%.0f", value)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"transaction_reference":"ref-1"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := mac.Sum(nil)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		match     bool
+	}{
+		{"same input", "secret", "1700000000", body, true},
+		{"other secret", "other", "1700000000", body, false},
+		{"other timestamp", "secret", "1700000001", body, false},
+		{"other body", "secret", "1700000000", []byte(`{"transaction_reference":"ref-2"}`), false},
+	}
+
+	for _, tt := range tests {
+		got := SignWebhook(tt.secret, tt.timestamp, tt.body)
+		if hmac.Equal(got, want) != tt.match {
+			t.Errorf("%s: signature %x, want match = %v", tt.name, got, tt.match)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "secret"
+	const tolerance = 5 * time.Minute
+	body := []byte(`{"event":"charge.success"}`)
+
+	defaultHeader := func(at time.Time, secret string, body []byte) http.Header {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		header := http.Header{}
+		header.Set(WebhookTimestampHeader, timestamp)
+		header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(SignWebhook(secret, timestamp, body)))
+		return header
+	}
+
+	paystackHeader := func(secret string, body []byte) http.Header {
+		mac := hmac.New(sha512.New, []byte(secret))
+		mac.Write(body)
+		header := http.Header{}
+		header.Set(PaystackSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+		return header
+	}
+
+	flutterwaveHeader := func(hash string) http.Header {
+		header := http.Header{}
+		header.Set(FlutterwaveSignatureHeader, hash)
+		return header
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name     string
+		provider PaymentProvider
+		header   http.Header
+		valid    bool
+	}{
+		{"default signed now", defaultProvider{}, defaultHeader(now, secret, body), true},
+		{"default signed within tolerance", defaultProvider{}, defaultHeader(now.Add(-4*time.Minute), secret, body), true},
+		{"default signed too long ago", defaultProvider{}, defaultHeader(now.Add(-6*time.Minute), secret, body), false},
+		{"default signed in the future", defaultProvider{}, defaultHeader(now.Add(6*time.Minute), secret, body), false},
+		{"default with the wrong secret", defaultProvider{}, defaultHeader(now, "other", body), false},
+		{"default for another body", defaultProvider{}, defaultHeader(now, secret, []byte(`{}`)), false},
+		{"default without headers", defaultProvider{}, http.Header{}, false},
+		{"paystack signed", paystackProvider{}, paystackHeader(secret, body), true},
+		{"paystack with the wrong secret", paystackProvider{}, paystackHeader("other", body), false},
+		{"paystack for another body", paystackProvider{}, paystackHeader(secret, []byte(`{}`)), false},
+		{"paystack without a signature", paystackProvider{}, http.Header{}, false},
+		{"flutterwave with the secret hash", flutterwaveProvider{}, flutterwaveHeader(secret), true},
+		{"flutterwave with another hash", flutterwaveProvider{}, flutterwaveHeader("other"), false},
+		{"flutterwave without a hash", flutterwaveProvider{}, http.Header{}, false},
+	}
+
+	for _, tt := range tests {
+		err := tt.provider.VerifySignature(tt.header, body, secret, tolerance)
+		switch {
+		case tt.valid && err != nil:
+			t.Errorf("%s: returned error: %v", tt.name, err)
+		case !tt.valid && !errors.Is(err, ErrInvalidSignature):
+			t.Errorf("%s: error = %v, want ErrInvalidSignature", tt.name, err)
+		}
+	}
+}
@@ -3,9 +3,10 @@ package service
 import (
 	"context"
 	json "encoding/json/v2"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"strconv"
 	"strings"
 	"time"
 
@@ -16,46 +17,104 @@ import (
 )
 
 type PaymentService interface {
-	ProcessPaymentNotification(req *models.PaymentNotificationRequest) error
+	// ParseProviderNotification translates a provider's payload into a
+	// notification for ProcessPaymentNotification
+	ParseProviderNotification(provider string, body []byte) (*models.PaymentNotificationRequest, error)
+	ProcessPaymentNotification(req *models.PaymentNotificationRequest) (*models.PaymentNotificationResult, error)
+	HandleNotificationJob(job *models.Job) error
+	// ImportPayments feeds each row of a CSV settlement file through
+	// ProcessPaymentNotification as a payment from provider, which defaults to
+	// DefaultPaymentProvider. Columns set in overrides replace the configured
+	// column mapping.
+	ImportPayments(file io.Reader, provider string, overrides *models.PaymentImportMapping) (*models.PaymentImportReport, error)
 }
 
+// ErrInvalidTransition is returned when a notification would move a
+// transaction to a status it cannot reach, such as COMPLETE to PENDING
+var ErrInvalidTransition = errors.New("invalid payment status transition")
+
+// ErrUnknownCustomer is returned when a notification's customer_id does not
+// belong to a customer with an account
+var ErrUnknownCustomer = errors.New("unknown customer")
+
+// ErrInvalidAmount is returned when a notification's transaction_amount is not
+// a positive amount
+var ErrInvalidAmount = errors.New("invalid transaction_amount")
+
 type paymentService struct {
-	customerRepo    repository.CustomerRepository
-	accountRepo     repository.AccountRepository
-	transactionRepo repository.TransactionRepository
-	cache           cache.Cache
+	customerRepo     repository.CustomerRepository
+	accountRepo      repository.AccountRepository
+	notificationRepo repository.PaymentNotificationRepository
+	uow              repository.UnitOfWork
+	cache            cache.Cache
+	strategy         models.AllocationStrategy
+	importMapping    models.PaymentImportMapping
 }
 
 func NewPaymentService(
 	customerRepo repository.CustomerRepository,
 	accountRepo repository.AccountRepository,
-	transactionRepo repository.TransactionRepository,
+	notificationRepo repository.PaymentNotificationRepository,
+	uow repository.UnitOfWork,
 	cache cache.Cache,
+	strategy models.AllocationStrategy,
+	importMapping *models.PaymentImportMapping,
 ) PaymentService {
 	return &paymentService{
-		customerRepo:    customerRepo,
-		accountRepo:     accountRepo,
-		transactionRepo: transactionRepo,
-		cache:           cache,
+		customerRepo:     customerRepo,
+		accountRepo:      accountRepo,
+		notificationRepo: notificationRepo,
+		uow:              uow,
+		cache:            cache,
+		strategy:         strategy,
+		importMapping:    *importMapping,
 	}
 }
 
-func (s *paymentService) ProcessPaymentNotification(req *models.PaymentNotificationRequest) error {
+// PaymentDateLayout is the transaction_date format sent by the payment provider
+const PaymentDateLayout = "2006-01-02 15:04:05"
+
+func (s *paymentService) ProcessPaymentNotification(req *models.PaymentNotificationRequest) (*models.PaymentNotificationResult, error) {
 	// Validate payment status
-	if req.PaymentStatus != "COMPLETE" {
-		return fmt.Errorf("only COMPLETE payment status is currently supported")
+	status := models.PaymentStatus(strings.ToUpper(req.PaymentStatus))
+	if !status.IsValid() {
+		return nil, fmt.Errorf("unsupported payment_status: %s", req.PaymentStatus)
 	}
 
-	// Parse customer ID (remove GIG prefix if present)
-	customerID, err := utils.ParseCustomerID(req.CustomerID)
+	// Parse transaction amount
+	amount, err := models.ParseMoney(req.TransactionAmount)
 	if err != nil {
-		return fmt.Errorf("invalid customer_id: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: must be greater than zero", ErrInvalidAmount)
 	}
 
-	// Parse transaction amount
-	amount, err := strconv.ParseFloat(req.TransactionAmount, 64)
+	// Parse transaction date
+	// Expected format: "2025-11-07 14:54:16"
+	transactionDate, err := time.Parse(PaymentDateLayout, req.TransactionDate)
 	if err != nil {
-		return fmt.Errorf("invalid transaction_amount: %w", err)
+		return nil, fmt.Errorf("invalid transaction_date format: %w", err)
+	}
+
+	provider := req.Provider
+	if provider == "" {
+		provider = DefaultPaymentProvider
+	}
+
+	notification := &models.PaymentNotification{
+		Provider:        provider,
+		Reference:       req.TransactionReference,
+		CustomerRef:     req.CustomerID,
+		PaymentStatus:   status,
+		Amount:          amount,
+		TransactionDate: transactionDate,
+	}
+
+	// Parse customer ID (remove GIG prefix if present)
+	customerID, err := utils.ParseCustomerID(req.CustomerID)
+	if err != nil {
+		return s.suspendPayment(notification, fmt.Errorf("%w: %w", ErrUnknownCustomer, err))
 	}
 
 	// Get the customer's account from cache or database
@@ -68,22 +127,25 @@ func (s *paymentService) ProcessPaymentNotification(req *models.PaymentNotificat
 	if err == nil && cachedData != nil {
 
 		if err := json.Unmarshal(cachedData, &account); err != nil {
-			return fmt.Errorf("failed to unmarshal cached account: %w", err)
+			return nil, fmt.Errorf("failed to unmarshal cached account: %w", err)
 		}
 
 	} else {
 
 		account, err = s.accountRepo.GetByCustomerID(customerID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return s.suspendPayment(notification, fmt.Errorf("%w: %w", ErrUnknownCustomer, err))
+		}
 		if err != nil {
-			return fmt.Errorf("account not found: %w", err)
+			return nil, fmt.Errorf("failed to get account: %w", err)
 		}
 
 		accountData, _ := json.Marshal(struct {
-			ID         int64     `json:"id"`
-			CustomerID int64     `json:"customer_id"`
-			Balance    float64   `json:"balance"`
-			CreatedAt  time.Time `json:"created_at"`
-			UpdatedAt  time.Time `json:"updated_at"`
+			ID         int64        `json:"id"`
+			CustomerID int64        `json:"customer_id"`
+			Balance    models.Money `json:"balance"`
+			CreatedAt  time.Time    `json:"created_at"`
+			UpdatedAt  time.Time    `json:"updated_at"`
 		}{
 			ID:         account.ID,
 			CustomerID: account.CustomerID,
@@ -98,42 +160,248 @@ func (s *paymentService) ProcessPaymentNotification(req *models.PaymentNotificat
 
 	}
 
-	go func() {
-		// Parse transaction date
-		// Expected format: "2025-11-07 14:54:16"
-		transactionDate, err := time.Parse("2006-01-02 15:04:05", req.TransactionDate)
-		if err != nil {
-			log.Printf("invalid transaction_date format: %v", err)
-			return
+	var created bool
+	err = s.uow.Do(func(repos *repository.Repositories) error {
+		notification, created, err = recordPaymentNotification(repos, notification)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPaymentNotificationResult(notification, !created), nil
+}
+
+// paymentTransactionReference is the reference of the transaction a provider's
+// payment is recorded under. References are only unique within a provider, so
+// all but the default provider's are prefixed with the provider's name.
+func paymentTransactionReference(provider, reference string) string {
+	if provider == "" || provider == DefaultPaymentProvider {
+		return reference
+	}
+	return provider + ":" + reference
+}
+
+// recordPaymentNotification stores the notification and enqueues its
+// processing job; it must run inside a unit of work so that both happen
+// atomically. A provider, reference and status that were already received are
+// a replay: the stored notification is returned and created is false.
+func recordPaymentNotification(repos *repository.Repositories, req *models.PaymentNotification) (notification *models.PaymentNotification, created bool, err error) {
+	existing, err := repos.Payments.GetByReference(req.Provider, req.Reference, req.PaymentStatus)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+
+	// Reject notifications that would move the transaction backwards
+	reference := paymentTransactionReference(req.Provider, req.Reference)
+	transaction, err := repos.Transactions.GetByReference(reference)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+	if transaction != nil && transaction.Status != req.PaymentStatus && !transaction.Status.CanTransitionTo(req.PaymentStatus) {
+		return nil, false, fmt.Errorf("%w: transaction %s is %s and cannot move to %s", ErrInvalidTransition, reference, transaction.Status, req.PaymentStatus)
+	}
+
+	notification, created, err = repos.Payments.Create(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record payment notification: %w", err)
+	}
+	if !created {
+		return notification, false, nil
+	}
+
+	payload, err := json.Marshal(paymentNotificationJob{NotificationID: notification.ID})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode payment notification job: %w", err)
+	}
+
+	if _, err := repos.Jobs.Enqueue(PaymentNotificationQueue, payload, paymentNotificationMaxAttempts); err != nil {
+		return nil, false, err
+	}
+
+	return notification, true, nil
+}
+
+// suspendPayment holds a completed payment whose customer is unknown as a
+// suspense payment, since the money has arrived even though it cannot be
+// credited. Notifications with any other status are rejected with reason.
+func (s *paymentService) suspendPayment(notification *models.PaymentNotification, reason error) (*models.PaymentNotificationResult, error) {
+	if notification.PaymentStatus != models.PaymentStatusComplete {
+		return nil, reason
+	}
+
+	var payment *models.SuspensePayment
+	var created bool
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		var err error
+		payment, created, err = repos.Suspense.Create(&models.SuspensePayment{
+			Provider:        notification.Provider,
+			Reference:       notification.Reference,
+			CustomerRef:     notification.CustomerRef,
+			Amount:          notification.Amount,
+			TransactionDate: notification.TransactionDate,
+			Reason:          reason.Error(),
+		})
+		if err != nil || !created {
+			return err
 		}
 
-		// Create transaction
-		createTransactionReq := &models.CreateTransactionRequest{
-			CustomerID:      customerID,
-			AccountID:       account.ID,
-			Reference:       req.TransactionReference,
-			Amount:          amount,
-			Status:          models.PaymentStatus(strings.ToUpper(req.PaymentStatus)),
-			Description:     req.TransactionDate,
-			TransactionDate: &transactionDate,
+		_, err = repos.Suspense.CreateEvent(&models.SuspenseEvent{
+			SuspenseID: payment.ID,
+			Action:     models.SuspenseActionReceived,
+			Actor:      notification.Provider,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		log.Printf("payment %s held in suspense: %v", notification.Reference, reason)
+	}
+
+	return models.NewSuspendedPaymentResult(payment, !created), nil
+}
+
+// PaymentNotificationQueue is the job queue that credits received payment notifications
+const PaymentNotificationQueue = "payment_notifications"
+
+const paymentNotificationMaxAttempts = 10
+
+type paymentNotificationJob struct {
+	NotificationID int64 `json:"notification_id"`
+}
+
+// HandleNotificationJob processes a queued payment notification. It is safe to
+// run more than once for the same notification.
+func (s *paymentService) HandleNotificationJob(job *models.Job) error {
+	var payload paymentNotificationJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid payment notification job payload: %w", err)
+	}
+
+	err := s.processNotification(payload.NotificationID)
+	if err != nil && job.IsLastAttempt() {
+		if markErr := s.notificationRepo.MarkFailed(payload.NotificationID, err.Error()); markErr != nil {
+			log.Printf("failed to mark payment notification %d as failed: %v", payload.NotificationID, markErr)
+		}
+	}
+
+	return err
+}
+
+func (s *paymentService) processNotification(notificationID int64) error {
+	notification, err := s.notificationRepo.GetByID(notificationID)
+	if err != nil {
+		return err
+	}
+
+	customerID, err := utils.ParseCustomerID(notification.CustomerRef)
+	if err != nil {
+		return fmt.Errorf("invalid customer_id: %w", err)
+	}
+
+	account, err := s.accountRepo.GetByCustomerID(customerID)
+	if err != nil {
+		return fmt.Errorf("account not found: %w", err)
+	}
+
+	err = s.applyNotification(notification.ID, customerID, account.ID)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache after successful credit
+	ctx := context.Background()
+	cacheKey := fmt.Sprintf("account:customer:%d", customerID)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		log.Printf("failed to invalidate cache: %v", err)
+	}
+
+	return nil
+}
+
+// applyNotification moves the transaction for the notification's reference
+// through the payment state machine and marks the notification as processed,
+// all in a single database transaction:
+//   - PENDING records the transaction without crediting the account
+//   - COMPLETE credits the account, either directly or from PENDING, and
+//     allocates the payment to the customer's open installments
+//   - FAILED and CANCELLED only update the transaction status
+func (s *paymentService) applyNotification(notificationID, customerID, accountID int64) error {
+	return s.uow.Do(func(repos *repository.Repositories) error {
+		// Lock the notification so a job that is picked up twice credits only once
+		notification, err := repos.Payments.LockByID(notificationID)
+		if err != nil {
+			return err
+		}
+		if notification.Status == models.NotificationStatusProcessed {
+			return nil
 		}
 
-		transaction, err := s.transactionRepo.Create(createTransactionReq)
+		reference := paymentTransactionReference(notification.Provider, notification.Reference)
+		transaction, err := repos.Transactions.LockByReference(reference)
 		if err != nil {
-			log.Printf("failed to create transaction: %v", err)
-			return
+			return err
+		}
+
+		switch {
+		case transaction == nil:
+			// First notification for this reference
+			createTransactionReq := &models.CreateTransactionRequest{
+				CustomerID:      customerID,
+				AccountID:       accountID,
+				Reference:       reference,
+				Type:            models.TransactionTypePayment,
+				Direction:       models.DirectionCredit,
+				Amount:          notification.Amount,
+				Status:          notification.PaymentStatus,
+				Description:     "Payment",
+				TransactionDate: &notification.TransactionDate,
+			}
+
+			transaction, err = repos.Transactions.Create(createTransactionReq)
+			if err != nil {
+				return fmt.Errorf("failed to create transaction: %w", err)
+			}
+
+		case transaction.Status == notification.PaymentStatus:
+			// Already in this status; nothing moves
+			return repos.Payments.MarkProcessed(notification.ID, transaction.AccountID, transaction.ID)
+
+		case !transaction.Status.CanTransitionTo(notification.PaymentStatus):
+			// Retrying cannot make an illegal transition legal, so fail the notification
+			reason := fmt.Sprintf("%v: transaction is %s and cannot move to %s", ErrInvalidTransition, transaction.Status, notification.PaymentStatus)
+			return repos.Payments.MarkFailed(notification.ID, reason)
+
+		default:
+			update := &models.UpdateTransactionRequest{Status: &notification.PaymentStatus}
+			if notification.PaymentStatus == models.PaymentStatusComplete {
+				update.Amount = &notification.Amount
+			}
+
+			transaction, err = repos.Transactions.Update(transaction.ID, update)
+			if err != nil {
+				return fmt.Errorf("failed to update transaction: %w", err)
+			}
 		}
 
-		// Credit the account (only if status is COMPLETE)
-		if createTransactionReq.Status == models.PaymentStatusComplete {
-			err := s.accountRepo.Credit(account.ID, transaction.ID, amount)
+		// Credit the account only when the payment completes
+		if transaction.Status == models.PaymentStatusComplete {
+			err := repos.Accounts.Credit(transaction.AccountID, transaction.ID, transaction.Amount)
 			if err != nil {
-				log.Printf("failed to credit account: %v", err)
-				return
+				return fmt.Errorf("failed to credit account: %w", err)
 			}
 
+			if _, err := allocatePayment(repos, s.strategy, transaction); err != nil {
+				return fmt.Errorf("failed to allocate payment: %w", err)
+			}
 		}
-	}()
 
-	return nil
+		return repos.Payments.MarkProcessed(notification.ID, transaction.AccountID, transaction.ID)
+	})
 }
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/cache"
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// ErrFutureEvaluationDate is returned when penalties are evaluated for a day
+// that has not happened yet
+var ErrFutureEvaluationDate = errors.New("penalties cannot be evaluated for a future date")
+
+// ErrPastEvaluationDate is returned when penalties are evaluated for a day
+// before today without asking for a backfill
+var ErrPastEvaluationDate = errors.New("penalties for a past date are only charged as a backfill")
+
+type PenaltyService interface {
+	CreateRule(ruleReq *models.CreatePenaltyRuleRequest) (*models.PenaltyRule, error)
+	GetRuleByID(id int64) (*models.PenaltyRule, error)
+	GetAllRules() ([]*models.PenaltyRule, error)
+	UpdateRule(id int64, ruleReq *models.UpdatePenaltyRuleRequest) (*models.PenaltyRule, error)
+	DeleteRule(id int64) error
+	// EvaluatePenalties charges late fees on installments overdue on date. It
+	// charges each installment at most once per day, so it is safe to run
+	// repeatedly. Only date is charged: days on which it never ran are not
+	// back-filled. Dates after today are rejected, and so are dates before
+	// today unless backfill is set by an admin charging a missed day.
+	EvaluatePenalties(date time.Time, backfill bool) (*models.PenaltyEvaluation, error)
+}
+
+type penaltyService struct {
+	penaltyRepo repository.PenaltyRepository
+	productRepo repository.ProductRepository
+	uow         repository.UnitOfWork
+	cache       cache.Cache
+}
+
+func NewPenaltyService(
+	penaltyRepo repository.PenaltyRepository,
+	productRepo repository.ProductRepository,
+	uow repository.UnitOfWork,
+	cache cache.Cache,
+) PenaltyService {
+	return &penaltyService{
+		penaltyRepo: penaltyRepo,
+		productRepo: productRepo,
+		uow:         uow,
+		cache:       cache,
+	}
+}
+
+func (s *penaltyService) CreateRule(ruleReq *models.CreatePenaltyRuleRequest) (*models.PenaltyRule, error) {
+	rule := &models.PenaltyRule{
+		Name:           strings.TrimSpace(ruleReq.Name),
+		Type:           ruleReq.Type,
+		Amount:         ruleReq.Amount,
+		RateBps:        ruleReq.RateBps,
+		GraceDays:      ruleReq.GraceDays,
+		InstallmentCap: ruleReq.InstallmentCap,
+		DeploymentCap:  ruleReq.DeploymentCap,
+	}
+
+	if ruleReq.ProductID != "" {
+		productID, err := utils.ParseProductID(ruleReq.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product_id: %w", err)
+		}
+
+		if _, err := s.productRepo.GetByID(productID); err != nil {
+			return nil, fmt.Errorf("product not found: %w", err)
+		}
+		rule.ProductID = &productID
+	}
+
+	created, err := s.penaltyRepo.CreateRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create penalty rule: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *penaltyService) GetRuleByID(id int64) (*models.PenaltyRule, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid penalty rule id")
+	}
+
+	return s.penaltyRepo.GetRuleByID(id)
+}
+
+func (s *penaltyService) GetAllRules() ([]*models.PenaltyRule, error) {
+	return s.penaltyRepo.GetRules()
+}
+
+func (s *penaltyService) UpdateRule(id int64, ruleReq *models.UpdatePenaltyRuleRequest) (*models.PenaltyRule, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid penalty rule id")
+	}
+
+	if ruleReq.Name != nil {
+		name := strings.TrimSpace(*ruleReq.Name)
+		ruleReq.Name = &name
+	}
+
+	return s.penaltyRepo.UpdateRule(id, ruleReq)
+}
+
+func (s *penaltyService) DeleteRule(id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid penalty rule id")
+	}
+
+	return s.penaltyRepo.DeleteRule(id)
+}
+
+func (s *penaltyService) EvaluatePenalties(date time.Time, backfill bool) (*models.PenaltyEvaluation, error) {
+	date = truncateToDate(date)
+	today := truncateToDate(time.Now())
+	if date.After(today) {
+		return nil, ErrFutureEvaluationDate
+	}
+	if date.Before(today) && !backfill {
+		return nil, ErrPastEvaluationDate
+	}
+	evaluation := &models.PenaltyEvaluation{Date: date}
+
+	rules, err := s.penaltyRepo.GetActiveRules()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return evaluation, nil
+	}
+
+	overdue, err := s.penaltyRepo.GetOverdueInstallments(date)
+	if err != nil {
+		return nil, err
+	}
+
+	charged := map[int64]bool{}
+	for _, item := range overdue {
+		rule := ruleFor(rules, item.ProductID)
+		if rule == nil || !rule.IsChargeable(item.Installment, date) {
+			continue
+		}
+		evaluation.Evaluated++
+
+		// Charge each installment in its own transaction so one failure does
+		// not hold back the rest; the next run retries it
+		amount, err := s.chargeInstallment(rule, item, date)
+		if err != nil {
+			log.Printf("failed to charge penalty on installment %d: %v", item.Installment.ID, err)
+			continue
+		}
+		if !amount.IsPositive() {
+			continue
+		}
+
+		evaluation.Charged++
+		evaluation.Amount += amount
+		charged[item.CustomerID] = true
+	}
+
+	// Invalidate the cached accounts of charged customers
+	ctx := context.Background()
+	for customerID := range charged {
+		cacheKey := fmt.Sprintf("account:customer:%d", customerID)
+		if err := s.cache.Delete(ctx, cacheKey); err != nil {
+			log.Printf("failed to invalidate cache: %v", err)
+		}
+	}
+
+	return evaluation, nil
+}
+
+// ruleFor picks the newest active rule for the product, falling back to the
+// newest rule that applies to every product
+func ruleFor(rules []*models.PenaltyRule, productID *int64) *models.PenaltyRule {
+	var fallback *models.PenaltyRule
+	for _, rule := range rules {
+		switch {
+		case rule.ProductID == nil:
+			if fallback == nil {
+				fallback = rule
+			}
+		case productID != nil && *rule.ProductID == *productID:
+			return rule
+		}
+	}
+	return fallback
+}
+
+// chargeInstallment posts one day's penalty on the installment as a FEE
+// transaction, capped by the rule, and returns the amount charged
+func (s *penaltyService) chargeInstallment(rule *models.PenaltyRule, item *models.OverdueInstallment, date time.Time) (models.Money, error) {
+	var amount models.Money
+
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		// Lock the installment so concurrent runs cannot charge the same day twice
+		installment, err := repos.Schedules.LockInstallment(item.Installment.ID)
+		if err != nil {
+			return err
+		}
+		if installment.Status == models.InstallmentStatusPaid {
+			return nil
+		}
+
+		exists, err := repos.Penalties.HasCharge(installment.ID, date)
+		if err != nil || exists {
+			return err
+		}
+
+		installmentTotal, scheduleTotal, err := repos.Penalties.GetChargedTotals(installment.ID, installment.ScheduleID)
+		if err != nil {
+			return err
+		}
+
+		amount = rule.Capped(rule.DailyCharge(installment), installmentTotal, scheduleTotal)
+		if !amount.IsPositive() {
+			return nil
+		}
+
+		chargeDate := date
+		transaction, err := repos.Transactions.Create(&models.CreateTransactionRequest{
+			CustomerID:      item.CustomerID,
+			AccountID:       item.AccountID,
+			Reference:       fmt.Sprintf("PEN-%d-%s", installment.ID, date.Format("20060102")),
+			Type:            models.TransactionTypeFee,
+			Direction:       models.DirectionDebit,
+			Amount:          amount,
+			Status:          models.PaymentStatusComplete,
+			Description:     "Late fee: " + rule.Name,
+			TransactionDate: &chargeDate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create fee transaction: %w", err)
+		}
+
+		if err := repos.Accounts.Debit(item.AccountID, transaction.ID, amount); err != nil {
+			return fmt.Errorf("failed to debit account: %w", err)
+		}
+
+		if _, err := repos.Penalties.CreateCharge(&models.PenaltyCharge{
+			RuleID:        &rule.ID,
+			ScheduleID:    installment.ScheduleID,
+			InstallmentID: installment.ID,
+			TransactionID: transaction.ID,
+			ChargeDate:    date,
+			Amount:        amount,
+		}); err != nil {
+			return err
+		}
+
+		return repos.Schedules.AddInstallmentPenalty(installment.ID, amount)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+func TestRuleFor(t *testing.T) {
+	productA, productB, productC := int64(1), int64(2), int64(3)
+
+	// Active rules come newest first
+	newestGeneral := &models.PenaltyRule{ID: 5}
+	newestForA := &models.PenaltyRule{ID: 4, ProductID: &productA}
+	olderGeneral := &models.PenaltyRule{ID: 3}
+	olderForA := &models.PenaltyRule{ID: 2, ProductID: &productA}
+	forB := &models.PenaltyRule{ID: 1, ProductID: &productB}
+	rules := []*models.PenaltyRule{newestGeneral, newestForA, olderGeneral, olderForA, forB}
+
+	tests := []struct {
+		name      string
+		rules     []*models.PenaltyRule
+		productID *int64
+		want      *models.PenaltyRule
+	}{
+		{"product rule beats a newer general rule", rules, &productA, newestForA},
+		{"other product's rule", rules, &productB, forB},
+		{"product without a rule falls back to the newest general rule", rules, &productC, newestGeneral},
+		{"deployment without a product gets the newest general rule", rules, nil, newestGeneral},
+		{"no general rule to fall back to", []*models.PenaltyRule{forB}, &productA, nil},
+		{"no rules", nil, &productA, nil},
+	}
+
+	for _, tt := range tests {
+		if got := ruleFor(tt.rules, tt.productID); got != tt.want {
+			t.Errorf("%s: ruleFor = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// noRules is a penalty repository without active rules, so an evaluation that
+// passes the date checks charges nothing
+type noRules struct {
+	repository.PenaltyRepository
+}
+
+func (noRules) GetActiveRules() ([]*models.PenaltyRule, error) {
+	return nil, nil
+}
+
+func TestEvaluatePenaltiesDate(t *testing.T) {
+	today := time.Now()
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		backfill bool
+		want     error
+	}{
+		{name: "today", date: today},
+		{name: "today as a backfill", date: today, backfill: true},
+		{name: "past date without backfill", date: today.AddDate(0, 0, -3), want: ErrPastEvaluationDate},
+		{name: "past date as a backfill", date: today.AddDate(0, 0, -3), backfill: true},
+		{name: "future date", date: today.AddDate(0, 0, 1), want: ErrFutureEvaluationDate},
+		{name: "future date as a backfill", date: today.AddDate(0, 0, 1), backfill: true, want: ErrFutureEvaluationDate},
+	}
+
+	s := NewPenaltyService(noRules{}, nil, nil, nil)
+	for _, tt := range tests {
+		_, err := s.EvaluatePenalties(tt.date, tt.backfill)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
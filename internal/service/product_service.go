@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+type ProductService interface {
+	CreateProduct(productReq *models.CreateProductRequest) (*models.Product, error)
+	GetProductByID(id int64) (*models.Product, error)
+	GetAllProducts() ([]*models.Product, error)
+	UpdateProduct(id int64, productReq *models.UpdateProductRequest) (*models.Product, error)
+	DeleteProduct(id int64) error
+}
+
+type productService struct {
+	productRepo repository.ProductRepository
+}
+
+func NewProductService(productRepo repository.ProductRepository) ProductService {
+	return &productService{
+		productRepo: productRepo,
+	}
+}
+
+func (s *productService) CreateProduct(productReq *models.CreateProductRequest) (*models.Product, error) {
+	productReq.Name = strings.TrimSpace(productReq.Name)
+
+	product, err := s.productRepo.Create(productReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return product, nil
+}
+
+func (s *productService) GetProductByID(id int64) (*models.Product, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid product id")
+	}
+
+	return s.productRepo.GetByID(id)
+}
+
+func (s *productService) GetAllProducts() ([]*models.Product, error) {
+	return s.productRepo.GetAll()
+}
+
+// UpdateProduct changes the catalog entry only; deployments already priced
+// from the product keep the price and terms copied onto their schedule
+func (s *productService) UpdateProduct(id int64, productReq *models.UpdateProductRequest) (*models.Product, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid product id")
+	}
+
+	if productReq.Name != nil {
+		name := strings.TrimSpace(*productReq.Name)
+		productReq.Name = &name
+	}
+
+	return s.productRepo.Update(id, productReq)
+}
+
+func (s *productService) DeleteProduct(id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid product id")
+	}
+
+	return s.productRepo.Delete(id)
+}
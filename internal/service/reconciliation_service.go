@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/cache"
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+type ReconciliationService interface {
+	// Reconcile compares every account balance with its transaction history.
+	// Unless dryRun is set, each mismatched balance is corrected with an
+	// ADJUSTMENT transaction.
+	Reconcile(dryRun bool) (*models.ReconciliationReport, error)
+}
+
+type reconciliationService struct {
+	accountRepo repository.AccountRepository
+	uow         repository.UnitOfWork
+	cache       cache.Cache
+}
+
+func NewReconciliationService(
+	accountRepo repository.AccountRepository,
+	uow repository.UnitOfWork,
+	cache cache.Cache,
+) ReconciliationService {
+	return &reconciliationService{
+		accountRepo: accountRepo,
+		uow:         uow,
+		cache:       cache,
+	}
+}
+
+func (s *reconciliationService) Reconcile(dryRun bool) (*models.ReconciliationReport, error) {
+	report := &models.ReconciliationReport{
+		DryRun:     dryRun,
+		RunAt:      time.Now().UTC(),
+		Mismatches: []*models.BalanceCheck{},
+	}
+
+	checks, err := s.accountRepo.GetBalanceChecks()
+	if err != nil {
+		return nil, err
+	}
+	report.AccountsChecked = len(checks)
+
+	for _, check := range checks {
+		if check.Difference() == 0 {
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, check)
+
+		if dryRun {
+			continue
+		}
+
+		if err := s.adjust(check, report.RunAt); err != nil {
+			log.Printf("failed to adjust account %d: %v", check.AccountID, err)
+			continue
+		}
+		if check.AdjustmentID != nil {
+			report.Adjusted++
+		} else if check.Difference() == 0 {
+			report.Resolved++
+		}
+	}
+
+	return report, nil
+}
+
+// adjust moves the account balance to the sum of its posted transactions. The
+// difference is recomputed under the account lock, since payments may have
+// been credited since the check was read; check is updated to match.
+func (s *reconciliationService) adjust(check *models.BalanceCheck, runAt time.Time) error {
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		account, err := repos.Accounts.LockByID(check.AccountID)
+		if err != nil {
+			return err
+		}
+
+		expected, err := repos.Transactions.GetPostedBalance(account.ID)
+		if err != nil {
+			return err
+		}
+
+		check.Balance = account.Balance
+		check.ExpectedBalance = expected
+
+		difference := check.Difference()
+		if difference == 0 {
+			return nil
+		}
+
+		direction := models.DirectionCredit
+		if difference < 0 {
+			direction = models.DirectionDebit
+		}
+
+		adjustment, err := repos.Transactions.Create(&models.CreateTransactionRequest{
+			CustomerID:  account.CustomerID,
+			AccountID:   account.ID,
+			Reference:   fmt.Sprintf("ADJ-%d-%s", account.ID, runAt.Format("20060102150405")),
+			Type:        models.TransactionTypeAdjustment,
+			Direction:   direction,
+			Amount:      difference.Abs(),
+			Status:      models.PaymentStatusComplete,
+			Description: fmt.Sprintf("Reconciliation: balance %s, expected %s", account.Balance, expected),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create adjustment transaction: %w", err)
+		}
+
+		if direction == models.DirectionCredit {
+			err = repos.Accounts.Credit(account.ID, adjustment.ID, difference.Abs())
+		} else {
+			err = repos.Accounts.Debit(account.ID, adjustment.ID, difference.Abs())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to adjust account balance: %w", err)
+		}
+
+		check.AdjustmentID = &adjustment.ID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache after adjusting the balance
+	ctx := context.Background()
+	cacheKey := fmt.Sprintf("account:customer:%d", check.CustomerID)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		log.Printf("failed to invalidate cache: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+// reconcileAccounts serves the balance checks read before the run and the
+// balances found once each account is locked
+type reconcileAccounts struct {
+	repository.AccountRepository
+	checks []*models.BalanceCheck
+	locked map[int64]models.Money
+}
+
+func (f *reconcileAccounts) GetBalanceChecks() ([]*models.BalanceCheck, error) {
+	return f.checks, nil
+}
+
+func (f *reconcileAccounts) LockByID(id int64) (*models.Account, error) {
+	return &models.Account{ID: id, CustomerID: id, Balance: f.locked[id]}, nil
+}
+
+func (f *reconcileAccounts) Credit(accountID, transactionID int64, amount models.Money) error {
+	return nil
+}
+
+func (f *reconcileAccounts) Debit(accountID, transactionID int64, amount models.Money) error {
+	return nil
+}
+
+type reconcileTransactions struct {
+	repository.TransactionRepository
+	posted map[int64]models.Money
+}
+
+func (f *reconcileTransactions) GetPostedBalance(accountID int64) (models.Money, error) {
+	return f.posted[accountID], nil
+}
+
+func (f *reconcileTransactions) Create(req *models.CreateTransactionRequest) (*models.Transaction, error) {
+	return &models.Transaction{ID: 100 + req.AccountID, AccountID: req.AccountID}, nil
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		dryRun         bool
+		lockedBalance  models.Money
+		wantAdjusted   int
+		wantResolved   int
+		wantUnresolved int
+	}{
+		{
+			name:          "mismatch still there under the lock is adjusted",
+			lockedBalance: -455000,
+			wantAdjusted:  1,
+		},
+		{
+			name:          "payment credited during the run resolves the mismatch",
+			lockedBalance: -450000,
+			wantResolved:  1,
+		},
+		{
+			name:           "dry run leaves the mismatch unresolved",
+			dryRun:         true,
+			lockedBalance:  -455000,
+			wantUnresolved: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		// Account 1 read 5000 below its posted balance; account 2 matched
+		accounts := &reconcileAccounts{
+			checks: []*models.BalanceCheck{
+				{AccountID: 1, CustomerID: 1, Balance: -455000, ExpectedBalance: -450000},
+				{AccountID: 2, CustomerID: 2, Balance: 1000, ExpectedBalance: 1000},
+			},
+			locked: map[int64]models.Money{1: tt.lockedBalance},
+		}
+		uow := fakeUnitOfWork{repos: &repository.Repositories{
+			Accounts:     accounts,
+			Transactions: &reconcileTransactions{posted: map[int64]models.Money{1: -450000}},
+		}}
+		s := NewReconciliationService(accounts, uow, missCache{})
+
+		report, err := s.Reconcile(tt.dryRun)
+		if err != nil {
+			t.Errorf("%s: returned error: %v", tt.name, err)
+			continue
+		}
+		if report.AccountsChecked != 2 || len(report.Mismatches) != 1 {
+			t.Errorf("%s: checked %d accounts with %d mismatches, want 2 with 1", tt.name, report.AccountsChecked, len(report.Mismatches))
+		}
+		if report.Adjusted != tt.wantAdjusted || report.Resolved != tt.wantResolved || report.Unresolved() != tt.wantUnresolved {
+			t.Errorf("%s: adjusted %d, resolved %d, unresolved %d; want %d, %d, %d", tt.name,
+				report.Adjusted, report.Resolved, report.Unresolved(),
+				tt.wantAdjusted, tt.wantResolved, tt.wantUnresolved)
+		}
+	}
+}
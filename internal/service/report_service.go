@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+// maxReportPeriods bounds the number of groups in a grouped report
+const maxReportPeriods = 60
+
+type ReportService interface {
+	GetPortfolioReport(req *models.PortfolioReportRequest) (*models.PortfolioReport, error)
+}
+
+type reportService struct {
+	reportRepo repository.ReportRepository
+}
+
+func NewReportService(reportRepo repository.ReportRepository) ReportService {
+	return &reportService{
+		reportRepo: reportRepo,
+	}
+}
+
+// GetPortfolioReport returns the portfolio snapshot as of req.AsOf and the
+// activity between req.From and req.To. When grouped by month, every calendar
+// month in the range also gets its own activity and a snapshot taken at the
+// end of the month (or at req.AsOf, if earlier).
+func (s *reportService) GetPortfolioReport(req *models.PortfolioReportRequest) (*models.PortfolioReport, error) {
+	asOf := truncateToDate(req.AsOf)
+	from := truncateToDate(req.From)
+	to := truncateToDate(req.To)
+
+	if to.Before(from) {
+		return nil, errors.New("from must not be after to")
+	}
+	if req.GroupBy != "" && req.GroupBy != models.ReportGroupByMonth {
+		return nil, errors.New("unsupported group_by, expected month")
+	}
+
+	snapshot, err := s.reportRepo.GetPortfolioSnapshot(asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.reportRepo.GetPortfolioActivity(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PortfolioReport{
+		Snapshot: snapshot,
+		Activity: activity,
+	}
+
+	if req.GroupBy != models.ReportGroupByMonth {
+		return report, nil
+	}
+
+	report.Periods = []*models.PortfolioPeriod{}
+	for start := from; !start.After(to); {
+		if len(report.Periods) == maxReportPeriods {
+			return nil, errors.New("date range is too long to group by month")
+		}
+
+		// The period runs to the end of the month, clipped to the range
+		end := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+
+		periodActivity, err := s.reportRepo.GetPortfolioActivity(start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotDate := end
+		if asOf.Before(snapshotDate) {
+			snapshotDate = asOf
+		}
+		periodSnapshot, err := s.reportRepo.GetPortfolioSnapshot(snapshotDate)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Periods = append(report.Periods, &models.PortfolioPeriod{
+			Period:   start.Format("2006-01"),
+			Activity: periodActivity,
+			Snapshot: periodSnapshot,
+		})
+
+		start = end.AddDate(0, 0, 1)
+	}
+
+	return report, nil
+}
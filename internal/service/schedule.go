@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+// maxTenor bounds the number of installments a single schedule may have
+const maxTenor = 1000
+
+// GenerateInstallments splits principal into tenor installments, the first due
+// on start and each following one a period later. Amounts are split evenly in
+// minor units; the remainder that cannot be split goes to the last installment
+// so the installments always sum to exactly the principal.
+func GenerateInstallments(principal models.Money, tenor int, frequency models.RepaymentFrequency, start time.Time) ([]*models.Installment, error) {
+	if tenor <= 0 || tenor > maxTenor {
+		return nil, fmt.Errorf("tenor must be between 1 and %d", maxTenor)
+	}
+	if !frequency.IsValid() {
+		return nil, fmt.Errorf("unsupported repayment frequency: %s", frequency)
+	}
+	if !principal.IsPositive() {
+		return nil, fmt.Errorf("principal must be greater than zero")
+	}
+
+	start = truncateToDate(start)
+	base := principal / models.Money(tenor)
+	remainder := principal - base*models.Money(tenor)
+
+	installments := make([]*models.Installment, 0, tenor)
+	for i := 0; i < tenor; i++ {
+		amount := base
+		if i == tenor-1 {
+			amount += remainder
+		}
+
+		installments = append(installments, &models.Installment{
+			Sequence:  i + 1,
+			DueDate:   addPeriods(start, frequency, i),
+			AmountDue: amount,
+			Status:    models.InstallmentStatusPending,
+		})
+	}
+
+	return installments, nil
+}
+
+// addPeriods moves date forward by n periods of the given frequency. Monthly
+// due dates keep the day of month of date, clamped to the end of shorter months.
+func addPeriods(date time.Time, frequency models.RepaymentFrequency, n int) time.Time {
+	switch frequency {
+	case models.RepaymentFrequencyDaily:
+		return date.AddDate(0, 0, n)
+	case models.RepaymentFrequencyWeekly:
+		return date.AddDate(0, 0, 7*n)
+	default:
+		year, month, day := date.Date()
+		firstOfMonth := time.Date(year, month+time.Month(n), 1, 0, 0, 0, 0, date.Location())
+		lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, date.Location())
+	}
+}
+
+// truncateToDate drops the time of day, keeping the calendar date in UTC
+func truncateToDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
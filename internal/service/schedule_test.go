@@ -0,0 +1,149 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGenerateInstallments(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Money
+		tenor     int
+		frequency models.RepaymentFrequency
+		start     time.Time
+		amounts   []models.Money
+		dueDates  []time.Time
+	}{
+		{
+			name:      "even split",
+			principal: 30000,
+			tenor:     3,
+			frequency: models.RepaymentFrequencyDaily,
+			start:     date(2025, time.January, 1),
+			amounts:   []models.Money{10000, 10000, 10000},
+			dueDates:  []time.Time{date(2025, time.January, 1), date(2025, time.January, 2), date(2025, time.January, 3)},
+		},
+		{
+			name:      "remainder on the last installment",
+			principal: 10000,
+			tenor:     3,
+			frequency: models.RepaymentFrequencyWeekly,
+			start:     date(2025, time.January, 1),
+			amounts:   []models.Money{3333, 3333, 3334},
+			dueDates:  []time.Time{date(2025, time.January, 1), date(2025, time.January, 8), date(2025, time.January, 15)},
+		},
+		{
+			name:      "principal smaller than tenor",
+			principal: 2,
+			tenor:     3,
+			frequency: models.RepaymentFrequencyDaily,
+			start:     date(2025, time.January, 1),
+			amounts:   []models.Money{0, 0, 2},
+			dueDates:  []time.Time{date(2025, time.January, 1), date(2025, time.January, 2), date(2025, time.January, 3)},
+		},
+		{
+			name:      "monthly from the 31st",
+			principal: 400,
+			tenor:     4,
+			frequency: models.RepaymentFrequencyMonthly,
+			start:     date(2025, time.January, 31),
+			amounts:   []models.Money{100, 100, 100, 100},
+			dueDates:  []time.Time{date(2025, time.January, 31), date(2025, time.February, 28), date(2025, time.March, 31), date(2025, time.April, 30)},
+		},
+		{
+			name:      "time of day is dropped",
+			principal: 100,
+			tenor:     1,
+			frequency: models.RepaymentFrequencyDaily,
+			start:     time.Date(2025, time.June, 15, 18, 45, 0, 0, time.UTC),
+			amounts:   []models.Money{100},
+			dueDates:  []time.Time{date(2025, time.June, 15)},
+		},
+	}
+
+	for _, tt := range tests {
+		installments, err := GenerateInstallments(tt.principal, tt.tenor, tt.frequency, tt.start)
+		if err != nil {
+			t.Errorf("%s: returned error: %v", tt.name, err)
+			continue
+		}
+		if len(installments) != tt.tenor {
+			t.Errorf("%s: got %d installments, want %d", tt.name, len(installments), tt.tenor)
+			continue
+		}
+
+		var total models.Money
+		for i, installment := range installments {
+			total += installment.AmountDue
+			if installment.Sequence != i+1 {
+				t.Errorf("%s: installment %d has sequence %d", tt.name, i, installment.Sequence)
+			}
+			if installment.AmountDue != tt.amounts[i] {
+				t.Errorf("%s: installment %d amount = %d, want %d", tt.name, i+1, installment.AmountDue, tt.amounts[i])
+			}
+			if !installment.DueDate.Equal(tt.dueDates[i]) {
+				t.Errorf("%s: installment %d due = %s, want %s", tt.name, i+1, installment.DueDate.Format(time.DateOnly), tt.dueDates[i].Format(time.DateOnly))
+			}
+			if installment.Status != models.InstallmentStatusPending {
+				t.Errorf("%s: installment %d status = %s, want PENDING", tt.name, i+1, installment.Status)
+			}
+		}
+		if total != tt.principal {
+			t.Errorf("%s: installments sum to %d, want %d", tt.name, total, tt.principal)
+		}
+	}
+}
+
+func TestGenerateInstallmentsInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal models.Money
+		tenor     int
+		frequency models.RepaymentFrequency
+	}{
+		{"zero tenor", 100, 0, models.RepaymentFrequencyDaily},
+		{"tenor above the maximum", 100, maxTenor + 1, models.RepaymentFrequencyDaily},
+		{"unknown frequency", 100, 1, "YEARLY"},
+		{"zero principal", 0, 1, models.RepaymentFrequencyDaily},
+		{"negative principal", -100, 1, models.RepaymentFrequencyDaily},
+	}
+
+	for _, tt := range tests {
+		if _, err := GenerateInstallments(tt.principal, tt.tenor, tt.frequency, date(2025, time.January, 1)); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestAddPeriods(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     time.Time
+		frequency models.RepaymentFrequency
+		n         int
+		want      time.Time
+	}{
+		{"daily across a month", date(2025, time.January, 30), models.RepaymentFrequencyDaily, 3, date(2025, time.February, 2)},
+		{"weekly across a year", date(2024, time.December, 25), models.RepaymentFrequencyWeekly, 2, date(2025, time.January, 8)},
+		{"monthly zero periods", date(2025, time.January, 31), models.RepaymentFrequencyMonthly, 0, date(2025, time.January, 31)},
+		{"monthly clamps to february", date(2025, time.January, 31), models.RepaymentFrequencyMonthly, 1, date(2025, time.February, 28)},
+		{"monthly clamps to leap february", date(2024, time.January, 31), models.RepaymentFrequencyMonthly, 1, date(2024, time.February, 29)},
+		{"monthly clamps to a 30 day month", date(2025, time.March, 31), models.RepaymentFrequencyMonthly, 1, date(2025, time.April, 30)},
+		{"monthly keeps the start day after a short month", date(2025, time.January, 31), models.RepaymentFrequencyMonthly, 2, date(2025, time.March, 31)},
+		{"monthly across a year", date(2025, time.November, 30), models.RepaymentFrequencyMonthly, 3, date(2026, time.February, 28)},
+		{"monthly mid month", date(2025, time.January, 15), models.RepaymentFrequencyMonthly, 12, date(2026, time.January, 15)},
+	}
+
+	for _, tt := range tests {
+		if got := addPeriods(tt.start, tt.frequency, tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s: addPeriods = %s, want %s", tt.name, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/pdf"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+// statementDateTimeLayout formats transaction dates on CSV and PDF statements
+const statementDateTimeLayout = "2006-01-02 15:04"
+
+// WriteStatementCSV writes the statement as CSV: a short header block, then
+// one row per transaction between the opening and closing balance rows
+func WriteStatementCSV(w io.Writer, statement *models.Statement) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"Customer", utils.FormatCustomerID(statement.Customer.ID), statement.Customer.FirstName + " " + statement.Customer.LastName},
+		{"Account", utils.FormatAccountID(statement.Account.ID)},
+		{"Period", statement.From.Format(models.DateLayout), statement.To.Format(models.DateLayout)},
+		{"Generated", statement.GeneratedAt.Format(time.RFC3339)},
+		{},
+		{"date", "transaction_id", "reference", "type", "description", "debit", "credit", "balance"},
+		{"", "", "", "", "Opening balance", "", "", statement.OpeningBalance.String()},
+	}
+
+	for _, line := range statement.Lines {
+		debit, credit := splitAmount(line)
+		rows = append(rows, []string{
+			line.TransactionDate.UTC().Format(statementDateTimeLayout),
+			utils.FormatTransactionID(line.TransactionID),
+			line.Reference,
+			string(line.Type),
+			line.Description,
+			debit,
+			credit,
+			line.Balance.String(),
+		})
+	}
+
+	rows = append(rows,
+		[]string{"", "", "", "", "Total", statement.TotalDebits.String(), statement.TotalCredits.String(), ""},
+		[]string{"", "", "", "", "Closing balance", "", "", statement.ClosingBalance.String()},
+	)
+
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write statement csv: %w", err)
+	}
+
+	return nil
+}
+
+// Column positions of the PDF statement table, in points from the left edge.
+// Amount columns give the right edge of the text.
+const (
+	pdfMargin      = 40.0
+	pdfColDate     = pdfMargin
+	pdfColRef      = 125.0
+	pdfColDesc     = 235.0
+	pdfColDebit    = 400.0
+	pdfColCredit   = 477.0
+	pdfRightEdge   = pdf.PageWidth - pdfMargin
+	pdfColBalance  = pdfRightEdge
+	pdfRowHeight   = 14.0
+	pdfBottomLimit = pdf.PageHeight - 60
+)
+
+// WriteStatementPDF renders the statement as an A4 PDF
+func WriteStatementPDF(w io.Writer, statement *models.Statement) error {
+	doc := pdf.New()
+	doc.AddPage()
+
+	y := 60.0
+	doc.Text(pdf.HelveticaBold, 18, pdfMargin, y, "Account Statement")
+	y += 28
+
+	details := [][2]string{
+		{"Customer", fmt.Sprintf("%s %s (%s)", statement.Customer.FirstName, statement.Customer.LastName, utils.FormatCustomerID(statement.Customer.ID))},
+		{"Email", statement.Customer.Email},
+		{"Account", utils.FormatAccountID(statement.Account.ID)},
+		{"Period", statement.From.Format(models.DateLayout) + " to " + statement.To.Format(models.DateLayout)},
+		{"Generated", statement.GeneratedAt.Format(time.RFC3339)},
+	}
+	for _, d := range details {
+		doc.Text(pdf.HelveticaBold, 10, pdfMargin, y, d[0])
+		doc.Text(pdf.Helvetica, 10, pdfMargin+70, y, d[1])
+		y += pdfRowHeight
+	}
+	y += 8
+
+	summary := [][2]string{
+		{"Opening balance", statement.OpeningBalance.String()},
+		{"Total credits", statement.TotalCredits.String()},
+		{"Total debits", statement.TotalDebits.String()},
+		{"Closing balance", statement.ClosingBalance.String()},
+	}
+	for _, s := range summary {
+		doc.Text(pdf.HelveticaBold, 10, pdfMargin, y, s[0])
+		doc.TextRight(10, pdfMargin+220, y, s[1])
+		y += pdfRowHeight
+	}
+	y += 14
+
+	header := func() {
+		doc.Text(pdf.HelveticaBold, 9, pdfColDate, y, "Date")
+		doc.Text(pdf.HelveticaBold, 9, pdfColRef, y, "Reference")
+		doc.Text(pdf.HelveticaBold, 9, pdfColDesc, y, "Description")
+		doc.Text(pdf.HelveticaBold, 9, pdfColDebit-30, y, "Debit")
+		doc.Text(pdf.HelveticaBold, 9, pdfColCredit-33, y, "Credit")
+		doc.Text(pdf.HelveticaBold, 9, pdfColBalance-40, y, "Balance")
+		doc.Line(pdfMargin, y+4, pdfRightEdge, y+4)
+		y += pdfRowHeight + 2
+	}
+	header()
+
+	row := func(date, reference, description, debit, credit, balance string) {
+		if y > pdfBottomLimit {
+			doc.AddPage()
+			y = 60
+			header()
+		}
+
+		doc.Text(pdf.Helvetica, 8, pdfColDate, y, date)
+		doc.Text(pdf.Helvetica, 8, pdfColRef, y, truncate(reference, 20))
+		doc.Text(pdf.Helvetica, 8, pdfColDesc, y, truncate(description, 30))
+		doc.TextRight(8, pdfColDebit, y, debit)
+		doc.TextRight(8, pdfColCredit, y, credit)
+		doc.TextRight(8, pdfColBalance, y, balance)
+		y += pdfRowHeight
+	}
+
+	row("", "", "Opening balance", "", "", statement.OpeningBalance.String())
+	for _, line := range statement.Lines {
+		debit, credit := splitAmount(line)
+		row(line.TransactionDate.UTC().Format(statementDateTimeLayout), line.Reference, line.Description, debit, credit, line.Balance.String())
+	}
+	doc.Line(pdfMargin, y-10, pdfRightEdge, y-10)
+	row("", "", "Total", statement.TotalDebits.String(), statement.TotalCredits.String(), "")
+	row("", "", "Closing balance", "", "", statement.ClosingBalance.String())
+
+	if _, err := doc.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write statement pdf: %w", err)
+	}
+
+	return nil
+}
+
+// splitAmount places the line amount in the debit or credit column
+func splitAmount(line *models.StatementLine) (debit, credit string) {
+	if line.Direction == models.DirectionCredit {
+		return "", line.Amount.String()
+	}
+	return line.Amount.String(), ""
+}
+
+// truncate shortens s to at most n characters, marking the cut with "..."
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
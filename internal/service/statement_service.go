@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+type StatementService interface {
+	GetStatement(customerID int64, from, to time.Time) (*models.Statement, error)
+}
+
+type statementService struct {
+	customerRepo    repository.CustomerRepository
+	accountRepo     repository.AccountRepository
+	transactionRepo repository.TransactionRepository
+}
+
+func NewStatementService(
+	customerRepo repository.CustomerRepository,
+	accountRepo repository.AccountRepository,
+	transactionRepo repository.TransactionRepository,
+) StatementService {
+	return &statementService{
+		customerRepo:    customerRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// GetStatement lists the posted transactions dated from from to to (both
+// inclusive) in date order. The opening balance is the sum of the posted
+// transactions dated before from, and each line carries the running balance.
+func (s *statementService) GetStatement(customerID int64, from, to time.Time) (*models.Statement, error) {
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if to.Before(from) {
+		return nil, errors.New("from must not be after to")
+	}
+
+	customer, err := s.customerRepo.GetByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first so the running balance reads top to bottom. The repository
+	// decides which transactions are posted, as it does for balances.
+	end := to.AddDate(0, 0, 1)
+	transactions, err := s.transactionRepo.GetPostedByAccountID(account.ID, end)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.Statement{
+		Customer:    customer,
+		Account:     account,
+		From:        from,
+		To:          to,
+		Lines:       []*models.StatementLine{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	balance := models.Money(0)
+	for _, transaction := range transactions {
+		balance += transaction.SignedAmount()
+		if transaction.TransactionDate.Before(from) {
+			statement.OpeningBalance = balance
+			continue
+		}
+
+		if transaction.Direction == models.DirectionCredit {
+			statement.TotalCredits += transaction.Amount
+		} else {
+			statement.TotalDebits += transaction.Amount
+		}
+
+		var description string
+		if transaction.Description != nil {
+			description = *transaction.Description
+		}
+
+		statement.Lines = append(statement.Lines, &models.StatementLine{
+			TransactionID:   transaction.ID,
+			TransactionDate: transaction.TransactionDate,
+			Reference:       transaction.Reference,
+			Type:            transaction.Type,
+			Description:     description,
+			Direction:       transaction.Direction,
+			Amount:          transaction.Amount,
+			Balance:         balance,
+		})
+	}
+	statement.ClosingBalance = balance
+
+	return statement, nil
+}
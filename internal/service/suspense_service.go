@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+	"github.com/emmrys-jay/gigmile/internal/utils"
+)
+
+type SuspenseService interface {
+	GetAll(status string) ([]*models.SuspensePayment, error)
+	GetByID(id int64) (*models.SuspensePayment, error)
+	// Match assigns an unmatched payment to a customer and records it as a
+	// COMPLETE payment notification, so the account is credited and the payment
+	// allocated by the normal payment flow
+	Match(id int64, req *models.MatchSuspenseRequest) (*models.SuspensePayment, error)
+	// Refund marks an unmatched payment as returned to the payer. The refund
+	// itself is made outside the system.
+	Refund(id int64, req *models.RefundSuspenseRequest) (*models.SuspensePayment, error)
+}
+
+// ErrSuspenseResolved is returned when a suspense payment has already been
+// matched or refunded
+var ErrSuspenseResolved = errors.New("suspense payment already resolved")
+
+type suspenseService struct {
+	suspenseRepo repository.SuspenseRepository
+	uow          repository.UnitOfWork
+}
+
+func NewSuspenseService(suspenseRepo repository.SuspenseRepository, uow repository.UnitOfWork) SuspenseService {
+	return &suspenseService{
+		suspenseRepo: suspenseRepo,
+		uow:          uow,
+	}
+}
+
+func (s *suspenseService) GetAll(status string) ([]*models.SuspensePayment, error) {
+	if status == "" {
+		return s.suspenseRepo.GetAll(nil)
+	}
+
+	filter := models.SuspenseStatus(strings.ToUpper(status))
+	if !filter.IsValid() {
+		return nil, fmt.Errorf("invalid status %q, expected UNMATCHED, MATCHED or REFUNDED", status)
+	}
+
+	return s.suspenseRepo.GetAll(&filter)
+}
+
+func (s *suspenseService) GetByID(id int64) (*models.SuspensePayment, error) {
+	return s.suspenseRepo.GetByID(id)
+}
+
+func (s *suspenseService) Match(id int64, req *models.MatchSuspenseRequest) (*models.SuspensePayment, error) {
+	customerID, err := utils.ParseCustomerID(req.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid customer_id: %w", err)
+	}
+
+	err = s.uow.Do(func(repos *repository.Repositories) error {
+		payment, err := lockUnresolvedSuspense(repos, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := repos.Accounts.GetByCustomerID(customerID); err != nil {
+			return err
+		}
+
+		// Recorded under the matched customer; the worker credits it from here
+		notification, created, err := recordPaymentNotification(repos, &models.PaymentNotification{
+			Provider:        payment.Provider,
+			Reference:       payment.Reference,
+			CustomerRef:     utils.FormatCustomerID(customerID),
+			PaymentStatus:   models.PaymentStatusComplete,
+			Amount:          payment.Amount,
+			TransactionDate: payment.TransactionDate,
+		})
+		if err != nil {
+			return err
+		}
+		if !created {
+			return fmt.Errorf("%w: a COMPLETE notification for %s was already received", ErrSuspenseResolved, payment.Reference)
+		}
+
+		payment.Status = models.SuspenseStatusMatched
+		payment.CustomerID = &customerID
+		payment.NotificationID = &notification.ID
+		return resolveSuspense(repos, payment, models.SuspenseActionMatched, req.Actor, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.suspenseRepo.GetByID(id)
+}
+
+func (s *suspenseService) Refund(id int64, req *models.RefundSuspenseRequest) (*models.SuspensePayment, error) {
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		payment, err := lockUnresolvedSuspense(repos, id)
+		if err != nil {
+			return err
+		}
+
+		payment.Status = models.SuspenseStatusRefunded
+		return resolveSuspense(repos, payment, models.SuspenseActionRefunded, req.Actor, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.suspenseRepo.GetByID(id)
+}
+
+func lockUnresolvedSuspense(repos *repository.Repositories, id int64) (*models.SuspensePayment, error) {
+	payment, err := repos.Suspense.LockByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != models.SuspenseStatusUnmatched {
+		return nil, fmt.Errorf("%w: suspense payment %d is %s", ErrSuspenseResolved, id, payment.Status)
+	}
+
+	return payment, nil
+}
+
+// resolveSuspense stores the payment's new status and records who resolved it
+func resolveSuspense(repos *repository.Repositories, payment *models.SuspensePayment, action models.SuspenseAction, actor, note string) error {
+	actor = strings.TrimSpace(actor)
+	payment.ResolvedBy = &actor
+	if note = strings.TrimSpace(note); note != "" {
+		payment.Note = &note
+	}
+
+	if err := repos.Suspense.Resolve(payment); err != nil {
+		return err
+	}
+
+	_, err := repos.Suspense.CreateEvent(&models.SuspenseEvent{
+		SuspenseID: payment.ID,
+		Action:     action,
+		Actor:      actor,
+		CustomerID: payment.CustomerID,
+		Note:       payment.Note,
+	})
+	return err
+}
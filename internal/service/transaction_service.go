@@ -1,24 +1,252 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/cache"
 	"github.com/emmrys-jay/gigmile/internal/models"
 	"github.com/emmrys-jay/gigmile/internal/repository"
 )
 
+var (
+	// ErrAlreadyReversed is returned when a transaction already has a reversal
+	ErrAlreadyReversed = errors.New("transaction has already been reversed")
+	// ErrNotReversible is returned for transactions that cannot be reversed
+	ErrNotReversible = errors.New("transaction cannot be reversed")
+)
+
 type TransactionService interface {
-	GetTransactionsByCustomer(customerID int64) ([]*models.Transaction, error)
+	GetTransactionsByCustomer(customerID int64, filter *models.TransactionFilter) ([]*models.Transaction, error)
+	ReverseTransaction(id int64, req *models.ReverseTransactionRequest) (*models.Transaction, error)
+	GetAllocations(id int64) ([]*models.PaymentAllocation, error)
 }
 
 type transactionService struct {
 	transactionRepo repository.TransactionRepository
+	allocationRepo  repository.AllocationRepository
+	uow             repository.UnitOfWork
+	cache           cache.Cache
 }
 
-func NewTransactionService(transactionRepo repository.TransactionRepository) TransactionService {
+func NewTransactionService(
+	transactionRepo repository.TransactionRepository,
+	allocationRepo repository.AllocationRepository,
+	uow repository.UnitOfWork,
+	cache cache.Cache,
+) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
+		allocationRepo:  allocationRepo,
+		uow:             uow,
+		cache:           cache,
 	}
 }
 
-func (s *transactionService) GetTransactionsByCustomer(customerID int64) ([]*models.Transaction, error) {
-	return s.transactionRepo.GetByCustomerID(customerID)
+func (s *transactionService) GetTransactionsByCustomer(customerID int64, filter *models.TransactionFilter) ([]*models.Transaction, error) {
+	return s.transactionRepo.FindByCustomerID(customerID, filter)
+}
+
+// GetAllocations returns how a payment was split across installments
+func (s *transactionService) GetAllocations(id int64) ([]*models.PaymentAllocation, error) {
+	if _, err := s.transactionRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.allocationRepo.GetByTransactionID(id)
+}
+
+// ReverseTransaction posts a compensating REVERSAL transaction in the opposite
+// direction of the original and moves the account balance back by the amount
+// the original actually posted to the ledger. Reversing a payment undoes its
+// allocations and reversing a late fee takes it off its installment.
+func (s *transactionService) ReverseTransaction(id int64, req *models.ReverseTransactionRequest) (*models.Transaction, error) {
+	var reversal *models.Transaction
+
+	err := s.uow.Do(func(repos *repository.Repositories) error {
+		// Lock the original so concurrent reversals are serialized
+		original, err := repos.Transactions.LockByID(id)
+		if err != nil {
+			return err
+		}
+
+		if original.Type == models.TransactionTypeReversal {
+			return fmt.Errorf("%w: %s is itself a reversal", ErrNotReversible, original.Reference)
+		}
+		if original.Type == models.TransactionTypeAdjustment {
+			return fmt.Errorf("%w: %s is a reconciliation adjustment", ErrNotReversible, original.Reference)
+		}
+
+		_, err = repos.Transactions.GetReversalOf(original.ID)
+		if err == nil {
+			return fmt.Errorf("%w: %s", ErrAlreadyReversed, original.Reference)
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		if original.Type == models.TransactionTypeDeployment {
+			if err := cancelDeployment(repos, original, req.Reason); err != nil {
+				return err
+			}
+		}
+
+		// Work out the net balance movement of the original from the ledger
+		entries, err := repos.Ledger.GetByTransactionID(original.ID)
+		if err != nil {
+			return err
+		}
+
+		var posted models.Money
+		for _, entry := range entries {
+			if entry.Direction == models.DirectionCredit {
+				posted += entry.Amount
+			} else {
+				posted -= entry.Amount
+			}
+		}
+
+		if posted == 0 {
+			return fmt.Errorf("%w: %s has not moved the account balance", ErrNotReversible, original.Reference)
+		}
+
+		direction := models.DirectionDebit
+		if posted < 0 {
+			direction = models.DirectionCredit
+		}
+
+		createTransactionReq := &models.CreateTransactionRequest{
+			CustomerID:  original.CustomerID,
+			AccountID:   original.AccountID,
+			Reference:   "REV-" + original.Reference,
+			Type:        models.TransactionTypeReversal,
+			Direction:   direction,
+			Amount:      posted.Abs(),
+			Status:      models.PaymentStatusComplete,
+			Description: req.Reason,
+			ReversalOf:  &original.ID,
+		}
+
+		reversal, err = repos.Transactions.Create(createTransactionReq)
+		if err != nil {
+			return fmt.Errorf("failed to create reversal transaction: %w", err)
+		}
+
+		if direction == models.DirectionDebit {
+			err = repos.Accounts.Debit(original.AccountID, reversal.ID, reversal.Amount)
+		} else {
+			err = repos.Accounts.Credit(original.AccountID, reversal.ID, reversal.Amount)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to adjust account balance: %w", err)
+		}
+
+		// The installments the original paid or charged must owe what they did before it
+		switch original.Type {
+		case models.TransactionTypePayment:
+			return unallocatePayment(repos, original, reversal)
+		case models.TransactionTypeFee:
+			return reversePenaltyCharge(repos, original)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache after the balance moved
+	ctx := context.Background()
+	cacheKey := fmt.Sprintf("account:customer:%d", reversal.CustomerID)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		log.Printf("failed to invalidate cache: %v", err)
+	}
+
+	return reversal, nil
+}
+
+// reversePenaltyCharge takes a late fee that is being reversed off its
+// installment. The charge is kept and marked reversed so the evaluator does
+// not charge the same day again. A fee that payments have already settled
+// cannot be reversed until those payments are.
+func reversePenaltyCharge(repos *repository.Repositories, transaction *models.Transaction) error {
+	charge, err := repos.Penalties.GetChargeByTransactionID(transaction.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Not a late fee posted by the penalty evaluator
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	installment, err := repos.Schedules.LockInstallment(charge.InstallmentID)
+	if err != nil {
+		return err
+	}
+
+	if installment.PenaltyOutstanding() < charge.Amount {
+		return fmt.Errorf("%w: %s has already been paid; reverse the payment first", ErrNotReversible, transaction.Reference)
+	}
+
+	if err := repos.Schedules.AddInstallmentPenalty(installment.ID, -charge.Amount); err != nil {
+		return err
+	}
+	installment.PenaltyDue -= charge.Amount
+
+	if err := repos.Penalties.MarkChargeReversed(charge.ID); err != nil {
+		return err
+	}
+
+	// The penalty may have been all that was left on the installment
+	if installment.Status == models.InstallmentStatusPaid || installment.Outstanding().IsPositive() {
+		return nil
+	}
+
+	paidAt := time.Now()
+	installment.Status = models.InstallmentStatusPaid
+	installment.PaidAt = &paidAt
+	if err := repos.Schedules.UpdateInstallmentPayment(installment); err != nil {
+		return err
+	}
+
+	return settleSchedules(repos, []int64{installment.ScheduleID})
+}
+
+// cancelDeployment closes the deployment recorded by a DEPLOYMENT transaction
+// that is being reversed, cancels its repayment schedule and returns its asset
+// to stock. Deployments that are already closed cannot be reversed.
+func cancelDeployment(repos *repository.Repositories, transaction *models.Transaction, reason string) error {
+	deployment, err := repos.Deployments.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	if !deployment.Status.CanTransitionTo(models.DeploymentStatusCancelled) {
+		return fmt.Errorf("%w: deployment %s is %s", ErrNotReversible, deployment.Reference, deployment.Status)
+	}
+
+	if _, err := transitionDeployment(repos, deployment.ID, models.DeploymentStatusCancelled, reason); err != nil {
+		return err
+	}
+
+	// A reversed deployment was recorded in error, so its asset is back in stock
+	if err := releaseAsset(repos, deployment, models.AssetStatusInStock); err != nil {
+		return err
+	}
+
+	if err := repos.Schedules.Cancel(deployment.ID); err != nil {
+		return err
+	}
+
+	if transaction.Status.CanTransitionTo(models.PaymentStatusCancelled) {
+		cancelled := models.PaymentStatusCancelled
+		if _, err := repos.Transactions.Update(transaction.ID, &models.UpdateTransactionRequest{Status: &cancelled}); err != nil {
+			return fmt.Errorf("failed to cancel deployment transaction: %w", err)
+		}
+	}
+
+	return nil
 }
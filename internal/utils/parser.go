@@ -10,6 +10,9 @@ const (
 	GIGPrefix = "GIG"
 	ACCPrefix = "ACC"
 	TRXPrefix = "TRX"
+	PRDPrefix = "PRD"
+	DEPPrefix = "DEP"
+	ASTPrefix = "AST"
 )
 
 // ParseCustomerID removes the GIG prefix from customer_id if present and returns the numeric ID
@@ -101,3 +104,93 @@ func FormatTransactionID(id int64) string {
 
 	return TRXPrefix + idStr
 }
+
+// ParseProductID removes the PRD prefix from product_id if present and returns the numeric ID
+func ParseProductID(productID string) (int64, error) {
+	// Remove PRD prefix if present
+	idStr := strings.TrimPrefix(productID, PRDPrefix)
+
+	// Parse to int64
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid product_id format: %s", productID)
+	}
+
+	return id, nil
+}
+
+// FormatProductID adds the PRD prefix to product ID with appropriate padding
+// Pads to 5 digits for IDs up to 99999 (total length 8: PRD + 5 digits)
+// For IDs exceeding 99999, uses the actual number of digits
+func FormatProductID(id int64) string {
+	idStr := strconv.FormatInt(id, 10)
+
+	// If id is 99999 or less, pad to 5 digits (total length will be 8: PRD + 5 digits)
+	if id <= 99999 {
+		padding := 5 - len(idStr)
+		idStr = strings.Repeat("0", padding) + idStr
+	}
+	// For IDs greater than 99999, use as-is (e.g., PRD100000)
+
+	return PRDPrefix + idStr
+}
+
+// ParseDeploymentID removes the DEP prefix from deployment_id if present and returns the numeric ID
+func ParseDeploymentID(deploymentID string) (int64, error) {
+	// Remove DEP prefix if present
+	idStr := strings.TrimPrefix(deploymentID, DEPPrefix)
+
+	// Parse to int64
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid deployment_id format: %s", deploymentID)
+	}
+
+	return id, nil
+}
+
+// FormatDeploymentID adds the DEP prefix to deployment ID with appropriate padding
+// Pads to 5 digits for IDs up to 99999 (total length 8: DEP + 5 digits)
+// For IDs exceeding 99999, uses the actual number of digits
+func FormatDeploymentID(id int64) string {
+	idStr := strconv.FormatInt(id, 10)
+
+	// If id is 99999 or less, pad to 5 digits (total length will be 8: DEP + 5 digits)
+	if id <= 99999 {
+		padding := 5 - len(idStr)
+		idStr = strings.Repeat("0", padding) + idStr
+	}
+	// For IDs greater than 99999, use as-is (e.g., DEP100000)
+
+	return DEPPrefix + idStr
+}
+
+// ParseAssetID removes the AST prefix from asset_id if present and returns the numeric ID
+func ParseAssetID(assetID string) (int64, error) {
+	// Remove AST prefix if present
+	idStr := strings.TrimPrefix(assetID, ASTPrefix)
+
+	// Parse to int64
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid asset_id format: %s", assetID)
+	}
+
+	return id, nil
+}
+
+// FormatAssetID adds the AST prefix to asset ID with appropriate padding
+// Pads to 5 digits for IDs up to 99999 (total length 8: AST + 5 digits)
+// For IDs exceeding 99999, uses the actual number of digits
+func FormatAssetID(id int64) string {
+	idStr := strconv.FormatInt(id, 10)
+
+	// If id is 99999 or less, pad to 5 digits (total length will be 8: AST + 5 digits)
+	if id <= 99999 {
+		padding := 5 - len(idStr)
+		idStr = strings.Repeat("0", padding) + idStr
+	}
+	// For IDs greater than 99999, use as-is (e.g., AST100000)
+
+	return ASTPrefix + idStr
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPeriodically calls fn immediately and then every interval until ctx is
+// cancelled. Errors are logged and the next run goes ahead as usual, so fn
+// must be safe to repeat.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(); err != nil {
+			log.Printf("%s failed: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/emmrys-jay/gigmile/internal/models"
+	"github.com/emmrys-jay/gigmile/internal/repository"
+)
+
+// HandlerFunc processes a single job. Returning an error schedules a retry
+// with exponential backoff until the job runs out of attempts.
+type HandlerFunc func(job *models.Job) error
+
+type Options struct {
+	Concurrency  int
+	PollInterval time.Duration
+	LockTimeout  time.Duration
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+// Pool runs handlers for queued jobs on a fixed number of goroutines
+type Pool struct {
+	jobRepo  repository.JobRepository
+	opts     Options
+	handlers map[string]HandlerFunc
+	wg       sync.WaitGroup
+}
+
+func NewPool(jobRepo repository.JobRepository, opts Options) *Pool {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 5 * time.Minute
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 5 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+
+	return &Pool{
+		jobRepo:  jobRepo,
+		opts:     opts,
+		handlers: map[string]HandlerFunc{},
+	}
+}
+
+// Register sets the handler for a queue. It must be called before Start.
+func (p *Pool) Register(queue string, handler HandlerFunc) {
+	p.handlers[queue] = handler
+}
+
+// Start launches the workers. They stop when ctx is cancelled.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.opts.Concurrency; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.run(ctx)
+		}()
+	}
+
+	log.Printf("Started %d job workers", p.opts.Concurrency)
+}
+
+// Wait blocks until all workers have stopped
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Pool) run(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain every ready job before waiting for the next tick
+		for p.processNext() {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext runs at most one job per registered queue and reports whether any job was found
+func (p *Pool) processNext() bool {
+	found := false
+
+	for queue, handler := range p.handlers {
+		job, err := p.jobRepo.ClaimNext(queue, p.opts.LockTimeout)
+		if err != nil {
+			log.Printf("failed to claim job on queue %s: %v", queue, err)
+			continue
+		}
+		if job == nil {
+			continue
+		}
+
+		found = true
+		p.process(job, handler)
+	}
+
+	return found
+}
+
+func (p *Pool) process(job *models.Job, handler HandlerFunc) {
+	err := safeCall(handler, job)
+	if err == nil {
+		if err := p.jobRepo.Complete(job.ID); err != nil {
+			log.Printf("failed to complete job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	if job.IsLastAttempt() {
+		log.Printf("job %d on queue %s failed permanently after %d attempts: %v", job.ID, job.Queue, job.Attempts, err)
+		if err := p.jobRepo.Bury(job.ID, err.Error()); err != nil {
+			log.Printf("failed to move job %d to dead state: %v", job.ID, err)
+		}
+		return
+	}
+
+	runAt := time.Now().Add(p.backoff(job.Attempts))
+	log.Printf("job %d on queue %s failed (attempt %d/%d), retrying at %s: %v", job.ID, job.Queue, job.Attempts, job.MaxAttempts, runAt.Format(time.RFC3339), err)
+	if err := p.jobRepo.Retry(job.ID, runAt, err.Error()); err != nil {
+		log.Printf("failed to reschedule job %d: %v", job.ID, err)
+	}
+}
+
+// backoff doubles the delay after every attempt, capped at MaxBackoff
+func (p *Pool) backoff(attempts int) time.Duration {
+	delay := p.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.opts.MaxBackoff {
+			return p.opts.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// safeCall turns a panicking handler into a failed attempt
+func safeCall(handler HandlerFunc, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(job)
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Providers send PENDING and then COMPLETE or FAILED for the same reference,
+-- so a notification is only a duplicate when both reference and status match
+DROP INDEX IF EXISTS uq_payment_notifications_reference;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payment_notifications_reference_status ON payment_notifications(reference, payment_status);
+CREATE INDEX IF NOT EXISTS idx_payment_notifications_reference ON payment_notifications(reference);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_payment_notifications_reference;
+DROP INDEX IF EXISTS uq_payment_notifications_reference_status;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payment_notifications_reference ON payment_notifications(reference);
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS type VARCHAR(50) NOT NULL DEFAULT 'PAYMENT';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS direction VARCHAR(10) NOT NULL DEFAULT 'CREDIT';
+
+-- Existing deployments are the rows that debited an account, or (before the
+-- ledger existed) the PENDING rows that did not come from a payment notification
+UPDATE transactions t
+SET type = 'DEPLOYMENT', direction = 'DEBIT'
+WHERE EXISTS (
+        SELECT 1 FROM ledger_entries l
+        WHERE l.transaction_id = t.id AND l.direction = 'DEBIT'
+    )
+    OR (
+        t.status = 'PENDING'
+        AND NOT EXISTS (SELECT 1 FROM payment_notifications n WHERE n.reference = t.reference)
+    );
+
+ALTER TABLE transactions ALTER COLUMN type DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN direction DROP DEFAULT;
+ALTER TABLE transactions ADD CONSTRAINT chk_transactions_type
+    CHECK (type IN ('PAYMENT', 'DEPLOYMENT', 'FEE', 'REVERSAL', 'ADJUSTMENT'));
+ALTER TABLE transactions ADD CONSTRAINT chk_transactions_direction
+    CHECK (direction IN ('DEBIT', 'CREDIT'));
+
+CREATE INDEX IF NOT EXISTS idx_transactions_type ON transactions(type);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_type;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_transactions_direction;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_transactions_type;
+ALTER TABLE transactions DROP COLUMN IF EXISTS direction;
+ALTER TABLE transactions DROP COLUMN IF EXISTS type;
+-- +goose StatementEnd
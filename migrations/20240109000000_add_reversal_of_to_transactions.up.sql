@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reversal_of INTEGER REFERENCES transactions(id) ON DELETE RESTRICT;
+
+-- A transaction can be reversed at most once
+CREATE UNIQUE INDEX IF NOT EXISTS uq_transactions_reversal_of ON transactions(reversal_of) WHERE reversal_of IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS uq_transactions_reversal_of;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reversal_of;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS repayment_schedules (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    principal DECIMAL(15, 2) NOT NULL,
+    tenor INTEGER NOT NULL CHECK (tenor > 0),
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY')),
+    start_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_repayment_schedules_transaction_id ON repayment_schedules(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_repayment_schedules_customer_id ON repayment_schedules(customer_id);
+
+CREATE TABLE IF NOT EXISTS installments (
+    id SERIAL PRIMARY KEY,
+    schedule_id INTEGER NOT NULL REFERENCES repayment_schedules(id) ON DELETE CASCADE,
+    sequence INTEGER NOT NULL,
+    due_date DATE NOT NULL,
+    amount_due DECIMAL(15, 2) NOT NULL,
+    amount_paid DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (schedule_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_installments_due_date ON installments(due_date);
+CREATE INDEX IF NOT EXISTS idx_installments_status ON installments(status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS installments;
+DROP TABLE IF EXISTS repayment_schedules;
+-- +goose StatementEnd
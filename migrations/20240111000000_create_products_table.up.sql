@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    asset_type VARCHAR(50) NOT NULL,
+    price DECIMAL(15, 2) NOT NULL CHECK (price > 0),
+    tenor INTEGER NOT NULL CHECK (tenor > 0),
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_asset_type ON products(asset_type);
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at);
+
+-- Schedules keep the product they were priced from; price and terms are
+-- already copied onto the schedule so later catalog changes do not rewrite them
+ALTER TABLE repayment_schedules ADD COLUMN IF NOT EXISTS product_id INTEGER REFERENCES products(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE repayment_schedules DROP COLUMN IF EXISTS product_id;
+DROP TABLE IF EXISTS products;
+-- +goose StatementEnd
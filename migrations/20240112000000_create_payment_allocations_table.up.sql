@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Installments carry penalties and fees next to their principal so a payment
+-- can settle each component separately
+ALTER TABLE installments ADD COLUMN IF NOT EXISTS fee_due DECIMAL(15, 2) NOT NULL DEFAULT 0.00;
+ALTER TABLE installments ADD COLUMN IF NOT EXISTS fee_paid DECIMAL(15, 2) NOT NULL DEFAULT 0.00;
+ALTER TABLE installments ADD COLUMN IF NOT EXISTS penalty_due DECIMAL(15, 2) NOT NULL DEFAULT 0.00;
+ALTER TABLE installments ADD COLUMN IF NOT EXISTS penalty_paid DECIMAL(15, 2) NOT NULL DEFAULT 0.00;
+
+CREATE TABLE IF NOT EXISTS payment_allocations (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    schedule_id INTEGER NOT NULL REFERENCES repayment_schedules(id) ON DELETE CASCADE,
+    installment_id INTEGER NOT NULL REFERENCES installments(id) ON DELETE CASCADE,
+    component VARCHAR(20) NOT NULL CHECK (component IN ('PENALTY', 'FEE', 'PRINCIPAL')),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_allocations_transaction_id ON payment_allocations(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_payment_allocations_installment_id ON payment_allocations(installment_id);
+CREATE INDEX IF NOT EXISTS idx_installments_schedule_status ON installments(schedule_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_installments_schedule_status;
+DROP TABLE IF EXISTS payment_allocations;
+ALTER TABLE installments DROP COLUMN IF EXISTS penalty_paid;
+ALTER TABLE installments DROP COLUMN IF EXISTS penalty_due;
+ALTER TABLE installments DROP COLUMN IF EXISTS fee_paid;
+ALTER TABLE installments DROP COLUMN IF EXISTS fee_due;
+-- +goose StatementEnd
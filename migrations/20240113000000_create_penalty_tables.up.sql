@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS penalty_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('FLAT', 'PERCENTAGE')),
+    amount DECIMAL(15, 2) NOT NULL DEFAULT 0.00 CHECK (amount >= 0),
+    rate_bps INTEGER NOT NULL DEFAULT 0 CHECK (rate_bps >= 0),
+    grace_days INTEGER NOT NULL DEFAULT 0 CHECK (grace_days >= 0),
+    installment_cap DECIMAL(15, 2) CHECK (installment_cap > 0),
+    deployment_cap DECIMAL(15, 2) CHECK (deployment_cap > 0),
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_penalty_rules_product_id ON penalty_rules(product_id);
+
+-- One charge per installment per day keeps the daily evaluator idempotent
+CREATE TABLE IF NOT EXISTS penalty_charges (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER REFERENCES penalty_rules(id) ON DELETE SET NULL,
+    schedule_id INTEGER NOT NULL REFERENCES repayment_schedules(id) ON DELETE CASCADE,
+    installment_id INTEGER NOT NULL REFERENCES installments(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    charge_date DATE NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (installment_id, charge_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_penalty_charges_schedule_id ON penalty_charges(schedule_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS penalty_charges;
+DROP TABLE IF EXISTS penalty_rules;
+-- +goose StatementEnd
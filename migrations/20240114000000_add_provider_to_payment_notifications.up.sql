@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notifications received before provider adapters existed came from the default provider
+ALTER TABLE payment_notifications ADD COLUMN IF NOT EXISTS provider VARCHAR(50) NOT NULL DEFAULT 'default';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payment_notifications DROP COLUMN IF EXISTS provider;
+-- +goose StatementEnd
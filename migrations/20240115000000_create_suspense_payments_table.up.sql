@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Completed payments whose customer could not be identified. The money has
+-- arrived, so they are held here until ops match them to a customer or refund them.
+CREATE TABLE IF NOT EXISTS suspense_payments (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    reference VARCHAR(255) NOT NULL UNIQUE,
+    customer_ref VARCHAR(255) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    transaction_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'UNMATCHED' CHECK (status IN ('UNMATCHED', 'MATCHED', 'REFUNDED')),
+    customer_id INTEGER REFERENCES customers(id),
+    notification_id INTEGER REFERENCES payment_notifications(id),
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_suspense_payments_status ON suspense_payments(status);
+
+-- Audit trail of who did what to each suspense payment
+CREATE TABLE IF NOT EXISTS suspense_payment_events (
+    id SERIAL PRIMARY KEY,
+    suspense_id INTEGER NOT NULL REFERENCES suspense_payments(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('RECEIVED', 'MATCHED', 'REFUNDED')),
+    actor VARCHAR(255) NOT NULL,
+    customer_id INTEGER REFERENCES customers(id),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_suspense_payment_events_suspense_id ON suspense_payment_events(suspense_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS suspense_payment_events;
+DROP TABLE IF EXISTS suspense_payments;
+-- +goose StatementEnd
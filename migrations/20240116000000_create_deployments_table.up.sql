@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS deployments (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id),
+    transaction_id INTEGER NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    reference VARCHAR(255) NOT NULL UNIQUE,
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'COMPLETED', 'DEFAULTED', 'REPOSSESSED', 'CANCELLED')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployments_customer_id ON deployments(customer_id);
+CREATE INDEX IF NOT EXISTS idx_deployments_status ON deployments(status);
+
+-- Backfill a deployment for every existing deployment transaction. Settled
+-- schedules are complete and reversed deployments are cancelled.
+INSERT INTO deployments (customer_id, account_id, product_id, transaction_id, reference, amount, status, start_date, end_date, closed_at, created_at, updated_at)
+SELECT
+    t.customer_id,
+    t.account_id,
+    s.product_id,
+    t.id,
+    t.reference,
+    t.amount,
+    CASE
+        WHEN EXISTS (SELECT 1 FROM transactions r WHERE r.reversal_of = t.id) THEN 'CANCELLED'
+        WHEN s.status = 'SETTLED' THEN 'COMPLETED'
+        ELSE 'ACTIVE'
+    END,
+    COALESCE(s.start_date, t.transaction_date::date),
+    COALESCE((SELECT MAX(i.due_date) FROM installments i WHERE i.schedule_id = s.id), s.start_date, t.transaction_date::date),
+    CASE WHEN s.status = 'SETTLED' THEN s.updated_at END,
+    t.created_at,
+    NOW()
+FROM transactions t
+LEFT JOIN repayment_schedules s ON s.transaction_id = t.id
+WHERE t.type = 'DEPLOYMENT'
+ON CONFLICT (transaction_id) DO NOTHING;
+
+ALTER TABLE repayment_schedules ADD COLUMN IF NOT EXISTS deployment_id INTEGER REFERENCES deployments(id) ON DELETE CASCADE;
+
+UPDATE repayment_schedules s
+SET deployment_id = d.id
+FROM deployments d
+WHERE d.transaction_id = s.transaction_id AND s.deployment_id IS NULL;
+
+-- Installments of cancelled deployments are no longer due
+UPDATE repayment_schedules s
+SET status = 'CANCELLED', updated_at = NOW()
+FROM deployments d
+WHERE d.id = s.deployment_id AND d.status = 'CANCELLED' AND s.status = 'ACTIVE';
+
+ALTER TABLE repayment_schedules ALTER COLUMN deployment_id SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_repayment_schedules_deployment_id ON repayment_schedules(deployment_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE repayment_schedules SET status = 'ACTIVE' WHERE status = 'CANCELLED';
+DROP INDEX IF EXISTS uq_repayment_schedules_deployment_id;
+ALTER TABLE repayment_schedules DROP COLUMN IF EXISTS deployment_id;
+DROP TABLE IF EXISTS deployments;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS assets (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+    vin VARCHAR(64) NOT NULL,
+    plate_number VARCHAR(32),
+    make VARCHAR(100) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    purchase_cost DECIMAL(15, 2) NOT NULL CHECK (purchase_cost >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'IN_STOCK' CHECK (status IN ('IN_STOCK', 'DEPLOYED', 'IN_REPAIR', 'REPOSSESSED', 'RETIRED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A chassis or plate can be registered again once the asset holding it is deleted
+CREATE UNIQUE INDEX IF NOT EXISTS uq_assets_vin ON assets(vin) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_assets_plate_number ON assets(plate_number) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_assets_status ON assets(status);
+CREATE INDEX IF NOT EXISTS idx_assets_deleted_at ON assets(deleted_at);
+
+-- Deployments recorded before the asset registry have no asset
+ALTER TABLE deployments ADD COLUMN IF NOT EXISTS asset_id INTEGER REFERENCES assets(id);
+CREATE INDEX IF NOT EXISTS idx_deployments_asset_id ON deployments(asset_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE deployments DROP COLUMN IF EXISTS asset_id;
+DROP TABLE IF EXISTS assets;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS guarantors (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    first_name VARCHAR(255) NOT NULL,
+    last_name VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(32) NOT NULL,
+    email VARCHAR(255),
+    address TEXT,
+    relationship VARCHAR(50) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    verified_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_guarantors_customer_id ON guarantors(customer_id);
+
+-- The same person cannot guarantee a customer twice
+CREATE UNIQUE INDEX IF NOT EXISTS uq_guarantors_customer_phone ON guarantors(customer_id, phone_number) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS guarantors;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Reversing a payment stores negative allocations against the reversal so
+-- allocations made before it still count in reports as of earlier dates
+ALTER TABLE payment_allocations DROP CONSTRAINT IF EXISTS payment_allocations_amount_check;
+ALTER TABLE payment_allocations ADD CONSTRAINT payment_allocations_amount_check CHECK (amount <> 0);
+
+-- Reversed charges are kept so the evaluator does not charge the same day again
+ALTER TABLE penalty_charges ADD COLUMN IF NOT EXISTS reversed_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_penalty_charges_transaction_id ON penalty_charges(transaction_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_penalty_charges_transaction_id;
+ALTER TABLE penalty_charges DROP COLUMN IF EXISTS reversed_at;
+DELETE FROM payment_allocations WHERE amount < 0;
+ALTER TABLE payment_allocations DROP CONSTRAINT IF EXISTS payment_allocations_amount_check;
+ALTER TABLE payment_allocations ADD CONSTRAINT payment_allocations_amount_check CHECK (amount > 0);
+-- +goose StatementEnd
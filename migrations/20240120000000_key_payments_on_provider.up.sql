@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- References are only unique within a provider, so the same reference from two
+-- providers is two payments
+DROP INDEX IF EXISTS uq_payment_notifications_reference_status;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payment_notifications_provider_reference_status ON payment_notifications(provider, reference, payment_status);
+
+ALTER TABLE suspense_payments DROP CONSTRAINT IF EXISTS suspense_payments_reference_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_suspense_payments_provider_reference ON suspense_payments(provider, reference);
+
+-- Payment transactions from providers other than the default one carry the
+-- provider in their reference. A transaction belongs to the provider of the
+-- first notification that created it.
+UPDATE transactions t
+SET reference = n.provider || ':' || t.reference, updated_at = NOW()
+FROM (
+    SELECT DISTINCT ON (transaction_id) transaction_id, provider
+    FROM payment_notifications
+    WHERE transaction_id IS NOT NULL
+    ORDER BY transaction_id, id
+) n
+WHERE n.transaction_id = t.id AND t.type = 'PAYMENT' AND n.provider <> 'default';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Strip only the provider prefix; the reference itself may contain colons
+UPDATE transactions
+SET reference = substr(reference, strpos(reference, ':') + 1), updated_at = NOW()
+WHERE type = 'PAYMENT' AND reference LIKE '%:%';
+
+DROP INDEX IF EXISTS uq_suspense_payments_provider_reference;
+ALTER TABLE suspense_payments ADD CONSTRAINT suspense_payments_reference_key UNIQUE (reference);
+
+DROP INDEX IF EXISTS uq_payment_notifications_provider_reference_status;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payment_notifications_reference_status ON payment_notifications(reference, payment_status);
+-- +goose StatementEnd